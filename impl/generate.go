@@ -1,22 +1,118 @@
+// The headers committed along with the generator are regenerated by go generate generate.go,
+// in the same commit as edits to the templates, and checked by -golden.
+//
+//go:generate go run generate.go -o interface.hpp
+//go:generate go run generate.go -N=3 -o testdata/interface_N3.hpp
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 	"text/template"
 )
 
 var header = `// DO NOT modify, this is a machine generated file.
 // DO NOT include directly, this is a implementation file.
 // See impl/README for details.
+{{- if checked}}
+// Calling a method of an empty interface throws bad_interface_call, derived from std::bad_function_call.
+// Methods with noexcept signatures call std::terminate instead.
+{{- end}}
+{{- if boundary}}
+// Exceptions thrown by held objects don't leave the methods of interfaces, see interface_error.
+{{- end}}
+{{- if module}}
+
+// Standard headers are included in the global module fragment.
+module;
+{{- end}}
 
 #include<memory>
+#include<array>
+#include<atomic>
 #include<type_traits>
 #include<cstddef>
+#include<functional>
+#include<new>
+#include<exception>
+#include<utility>
+#include<tuple>
+#include<stdexcept>
+#include<cstdarg>
+#include<cstring>
+{{- if pmr}}
+#include<memory_resource>
+{{- end}}
+{{- if tag}}
+#include<cstdint>
+{{- end}}
+{{- if comparable}}
+#include<compare>
+{{- end}}
+{{- if rtti}}
+#include<typeinfo>
+{{- end}}
+{{- if stream}}
+#include<iosfwd>
+{{- end}}
+{{- if debugChecks}}
+#include<cassert>
+{{- end}}
+{{- if reflect}}
+#include<string_view>
+{{- end}}
+{{- if boundary}}
+#include<system_error>
+{{- end}}
+{{- range includes}}
+#include{{.}}
+{{- end}}
+{{- if visibility}}
+
+// Attributes of the implementation details and exceptions{{if exported}}, and of the interfaces{{end}}, such as their
+// visibility, so that all shared libraries agree on the thunks and vtables identifying types.
+#ifndef INTERFACE_VISIBILITY
+#define INTERFACE_VISIBILITY {{visibility}}
+#endif
+{{- end}}
+{{- if module}}
+
+// Only the public names are exported, the implementation details are reachable through them.
+export module {{module}};
+{{- end}}
+
+{{if checked -}}
+// Thrown by calling a method of an empty interface.
+{{export}}struct {{if visibility}}INTERFACE_VISIBILITY {{end}}bad_interface_call : ::std::bad_function_call
+{
+    const char* what() const noexcept override { return "bad_interface_call"; }
+};
+
+{{end -}}
+// Thrown by copying an interface holding an object that isn't copy constructible.
+{{export}}struct {{if visibility}}INTERFACE_VISIBILITY {{end}}bad_interface_copy : ::std::exception
+{
+    const char* what() const noexcept override { return "bad_interface_copy"; }
+};
+
+// Stands for the interface in the return type of a signature, such as interface_self&(int), making
+// the method return the interface itself for chaining calls. Returning interface& instead returns
+// whatever the object returns.
+{{export}}struct interface_self {};
 
 // Implementaion namespace.
-namespace interface_detail
+namespace {{if visibility}}INTERFACE_VISIBILITY {{end}}{{detail}}
 {
     struct interface_tag {}; // As extra parameter for certain implementation functions to avoid namespace pollution.
 
@@ -24,541 +120,5036 @@ namespace interface_detail
     struct is_interface : std::is_base_of<interface_tag, T> {};
 
     template<typename T>
-    inline static constexpr bool is_interface_v = is_interface<T>::value;
+    inline constexpr bool is_interface_v = is_interface<T>::value;
+
+    // Views refer to objects rather than hold them, so they are not interfaces themselves.
+    struct view_tag {};
+
+    template<typename T>
+    inline constexpr bool is_view_v = std::is_base_of_v<view_tag, T>;
+
+    template<typename T>
+    struct is_in_place_type : std::false_type {};
+    template<typename T>
+    struct is_in_place_type<std::in_place_type_t<T>> : std::true_type {};
 
     // Base case factory for type erased method call.
     // Shouldn't be called. Working factories within the defined interface.
     struct nothing
     {
-        template<typename... Args>
-        void call(void*, Args&&...) {}
-    };
+        template<typename P, typename... Args>
+        void call(P*, Args&&...) {}
+{{- if checked}}
 
-    // erasure_fn is a traits class that handles void return types gracefully.
-    template<typename Signature, typename Factory = nothing>
-    struct erasure_fn;
+        // Calls on an empty interface end up here instead of dereferencing null.
+        // Not a throw expression within the methods, which may be noexcept.
+        [[noreturn]] static void empty() { throw ::bad_interface_call{}; }
+{{- end}}
+    };
+{{- if boundary}}
 
-    template<typename Ret, typename... Args, typename Factory>
-    struct erasure_fn<Ret(Args...), Factory> : Factory
+    // Category of the errors of exceptions that aren't std::system_error or std::bad_alloc.
+    struct error_category : std::error_category
     {
-        using type = Ret(void*, Args...);
-        using return_type = Ret;
-        static constexpr Ret value(void* p, Args... args)
-        {
-            if constexpr(std::is_void_v<Ret>)
-                Factory::call(p, std::forward<Args>(args)...);
-            else
-                return Factory::call(p, std::forward<Args>(args)...);
-        };
+        const char* name() const noexcept override { return "interface"; }
+        std::string message(int) const override { return "exception thrown by the held object"; }
     };
 
-    // Unified interface to access stored object.
-    // Stored pointer signifies reference semantics.
-    template<typename T>
-    decltype(auto) as_object(void* p)
+    inline const error_category& category() noexcept
     {
-        if constexpr(std::is_pointer_v<T>)
-            return **static_cast<T*>(p);
-        else
-            return *static_cast<T*>(p);
+        static const error_category c;
+        return c;
     }
 
-    // Type erased special member functions.
-    struct thunk
-    {
-        void (*copy)(void* dst, const void* src) = nullptr;
-        void (*move)(void* dst, void* src) = nullptr;
-        void (*destroy)(void* p) noexcept = nullptr;
-        std::size_t size = 0;
-    };
-
-    // Address of t acts as RTTI.
-    template<typename T, bool = std::is_constructible_v<T, const T&>>
-    struct thunk_storage
-    {
-        inline static constexpr thunk t = {
-            [](void* dst, const void* src) {
-                new (dst) T{*static_cast<const T*>(src)};
-            },
-            [](void* dst, void* src) {
-                new (dst) T{std::move(*static_cast<T*>(src))};
-            },
-            [](void* p) noexcept {
-                static_cast<T*>(p)->~T();
-            },
-            sizeof(T)
-        };
-    };
-    template<typename T>
-    struct thunk_storage<T, false>
+    // Error of the last method called on this thread whose object threw.
+    inline std::error_code& last_error() noexcept
     {
-        inline static constexpr thunk t = {
-            nullptr,
-            nullptr,
-            [](void* p) noexcept {
-                static_cast<T*>(p)->~T();
-            },
-            sizeof(T)
-        };
-    };
+        thread_local std::error_code e;
+        return e;
+    }
+#if defined(__cpp_exceptions) || defined(__EXCEPTIONS) || defined(_CPPUNWIND)
 
-    template<typename T>
-    constexpr const thunk* get_thunk()
+    // Error code of the exception being handled.
+    inline std::error_code current_error() noexcept
     {
-        // Returns same thunk for all pointer types, used to determine whether
-        // interface has reference semantics.
-        if constexpr (std::is_pointer_v<T>)
-            return &thunk_storage<void*>::t;
-        else
-            return &thunk_storage<T>::t;
+        try
+        {
+            throw;
+        }
+        catch(const std::system_error& e)
+        {
+            return e.code();
+        }
+        catch(const std::bad_alloc&)
+        {
+            return std::make_error_code(std::errc::not_enough_memory);
+        }
+        catch(...)
+        {
+            return {1, category()};
+        }
     }
+#endif
 
-    // All pointer thunks are void* thunks.
-    constexpr bool is_pointer_thunk(const thunk* t)
+    // Calls f, keeping exceptions from propagating through the vtable, which is left noexcept.
+    // The exception is recorded as the last error instead, and a value initialized Ret returned.
+    // References and types that can't be value initialized have nothing to return, and terminate.
+    // Without exceptions enabled nothing can be thrown, and f is called as is.
+    template<typename Ret, typename F>
+    Ret boundary(F&& f) noexcept
     {
-        return t == get_thunk<void*>();
+#if defined(__cpp_exceptions) || defined(__EXCEPTIONS) || defined(_CPPUNWIND)
+        try
+        {
+            return f();
+        }
+        catch(...)
+        {
+            last_error() = current_error();
+            if constexpr(std::is_void_v<Ret>)
+                return;
+            else if constexpr(!std::is_reference_v<Ret> && std::is_default_constructible_v<Ret>)
+                return Ret();
+            else
+                std::terminate();
+        }
+#else
+        return f();
+#endif
     }
-}
-
-// For ADL purposes.
-template<typename T, typename I>
-void target(I&&, ::interface_detail::interface_tag);
+{{- end}}
 
-// For creating anonymous variables.
-#define INTERFACE_CONCAT_DIRECT(x, y) x##y
-#define INTERFACE_CONCAT(x, y) INTERFACE_CONCAT_DIRECT(x, y)
-#define INTERFACE_APPEND_LINE(x) INTERFACE_CONCAT(x, __LINE__)
+{{- if debugChecks}}
+    // Written over the pointers of destroyed interfaces, never the address of an object, thunk or vtable.
+    inline char poisoned = 0;
 
-#ifdef INTERFACE_FOR_EXPOSITION_ONLY
-// The following is used only as documentation to the implementation of interface.
-// SIGNATURE and METHOD_NAME are the parameters passed in by the user.
-// The multitudes of versions each have a different arity.
+    // Poisons the pointers of a destroyed interface, failing the checks of later uses of it.
+    template<typename... Ps>
+    void poison([[maybe_unused]] Ps*&... ps) noexcept
+    {
+#ifndef NDEBUG
+        ((ps = reinterpret_cast<Ps*>(&poisoned)), ...);
+#endif
+    }
 
-// Inherits from interface_tag for type traits is_interface.
-class INTERFACE_APPEND_LINE(interface__) : ::interface_detail::interface_tag
-{
-    // Alias for both readability and for recursively defined functions:
-    // user may provide a function signature including interface.
-    using interface = INTERFACE_APPEND_LINE(interface__);
+    // Asserts the invariant of interfaces: the object, thunk and vtable pointers are either all null
+    // or all set, and not those of a destroyed interface.
+    template<typename... Ps>
+    void check([[maybe_unused]] const Ps*... ps) noexcept
+    {
+#ifndef NDEBUG
+        assert(((static_cast<const void*>(ps) != &poisoned) && ...) && "interface used after destruction");
+        assert((((ps != nullptr) && ...) || ((ps == nullptr) && ...)) && "interface in an inconsistent state");
+#endif
+    }
 
-    // Used in dispatching function call.
-    // Used in converting from one interface to another to bypass access level.
-    // interface_tag used to avoid namespace pollution, however improbable.
-    friend auto get_##METHOD_NAME0(const interface& i, ::interface_detail::interface_tag)
+    // Also asserts the interface isn't empty, calling a method of which is undefined without -checked,
+    // such as one moved from.
+    template<typename... Ps>
+    void check_held([[maybe_unused]] const Ps*... ps) noexcept
     {
-        using std::get;
-        return get<0>(i._vtable);
+#ifndef NDEBUG
+        check(ps...);
+        assert(((ps != nullptr) && ...) && "method called on an empty interface");
+#endif
     }
 
-    // Factory for type erased method call
-    // Suffix used to avoid name collisions.
-    template <typename T>
-    struct METHOD_NAME0##_0_factory
+{{- end}}
+    // Calls Factory, substitution fails if the object doesn't provide the method.
+    template<typename Factory>
+    struct factory_call
     {
-        template <typename... Args>
-        static decltype(auto) call(void* p, Args&&... args)
-        {
-            return ::interface_detail::as_object<T>(p).METHOD_NAME0(::std::forward<Args>(args)...);
-        }
+        template<typename P, typename... Args>
+        auto operator()(P* p, Args&&... args) const -> decltype(Factory::call(p, std::forward<Args>(args)...));
     };
 
-    // Used in target.
-    // Used in converting from one interface to another to bypass access level.
-    // interface_tag used to avoid namespace pollution, however improbable.
-    friend auto fetch_ptr(const interface& i, ::interface_detail::interface_tag) { return i._ptr; }
-
-    // Used in target.
-    // Used in converting from one interface to another to bypass access level.
-    // interface_tag used to avoid namespace pollution, however improbable.
-    friend auto&& fetch_thunk(const interface& i, ::interface_detail::interface_tag)
+    // Calls the method of the object through Factory, substitution fails if it isn't provided.
+    // Detects whether methods with a default are provided by the object.
+    template<typename Factory>
+    struct method_call
     {
-        return i._t;
-    }
+        template<typename P, typename... Args>
+        auto operator()(P* p, Args&&... args) const -> decltype(Factory::method(p, std::forward<Args>(args)...));
+    };
 
-    template<typename I>
-    void construct(I&& i)
+    // Data members are accessed in place of methods without parameters, as lvalues so that they
+    // can be written through. Invocable members are called like methods instead.
+    // Taking the address rules out bit-fields, which const references would bind copies of.
+    template<typename T>
+    auto field(T* t) noexcept -> std::enable_if_t<!std::is_invocable_v<T&>, T&>
     {
-        if(!i)
-            return;
+        return *t;
+    }
 
-        auto p = fetch_ptr(i, ::interface_detail::interface_tag{});
-        auto t = fetch_thunk(i, ::interface_detail::interface_tag{});
+    // Tags the object when selecting among methods sharing a name.
+    // Const interfaces only select const signatures, others prefer non-const ones like member functions.
+    // Const and non-const signatures of a name each have their own slot, so pairs such as those of
+    // std::vector::operator[] erase both member functions, selected by the constness of the interface.
+    struct const_tag {};
+    struct mutable_tag : const_tag {};
+{{- if refs}}
 
-        // Exception safe buffer allocation.
-        auto buf = ::std::unique_ptr<::std::byte[]>(new ::std::byte[t->size]);
+    // Points to the object of methods qualified &&.
+    struct rvalue;
+{{- end}}
 
-        // Other constructor guarantees the two following calls are both valid.
-        if constexpr(::std::is_lvalue_reference_v<I> || ::std::is_const_v<I>)
-            t->copy(buf.get(), p);
-        else
-            t->move(buf.get(), p);
+    template<std::size_t I>
+    using index = std::integral_constant<std::size_t, I>;
 
-        // Avoid [basic.life]/8 where original pointer cannot be used to refer to the newly
-        // constructed object.
-        _ptr = ::std::launder(buf.get());
+    // Selected by calls omitting the last N arguments of method I, which are given default arguments.
+    // Not an index, so the methods themselves aren't called with too few arguments.
+    template<std::size_t I, std::size_t N>
+    struct omitted {};
 
-        buf.release();
-        _t = t;
+    // Function type taking the parameters of the signature but the last N, returning omitted<I, N>.
+    template<std::size_t I, std::size_t N, typename Tag, typename Args,
+        typename Seq = std::make_index_sequence<std::tuple_size_v<Args> - N>>
+    struct omitted_selector;
+    template<std::size_t I, std::size_t N, typename Tag, typename Args, std::size_t... Is>
+    struct omitted_selector<I, N, Tag, Args, std::index_sequence<Is...>>
+    {
+        using type = omitted<I, N>(Tag, std::tuple_element_t<Is, Args>...);
+    };
 
-        // Magic here. Constructs _vtable by name at compile time.
-        // This is the reason why we can't use polymorphic classes as in std::function.
-        _vtable = {
-            get_##METHOD_NAME0(i, ::interface_detail::interface_tag{}),
-        };
-    }
+    // The I-th of the signatures of an interface, for method_signature.
+    template<std::size_t I, typename... Signatures>
+    struct nth_signature;
+    template<typename S, typename... Signatures>
+    struct nth_signature<0, S, Signatures...> { using type = S; };
+    template<std::size_t I, typename S, typename... Signatures>
+    struct nth_signature<I, S, Signatures...> : nth_signature<I - 1, Signatures...> {};
 
-  public:
-    INTERFACE_APPEND_LINE(interface__)() = default;
-    INTERFACE_APPEND_LINE(interface__)(interface&& other) noexcept { swap(*this, other); }
-    INTERFACE_APPEND_LINE(interface__)(const interface& other) { construct(other); }
+    // Compositions number the methods of A followed by those of B.
+    template<typename A, typename B, std::size_t I, bool = (I < A::method_count)>
+    struct composed_signature { using type = typename A::template method_signature<I>; };
+    template<typename A, typename B, std::size_t I>
+    struct composed_signature<A, B, I, false> { using type = typename B::template method_signature<I - A::method_count>; };
 
-    // SFINAE on whether argument is an interface.
-    // This is the converting constructor from other superset interfaces.
-    template <typename I,
-              ::std::enable_if_t<::interface_detail::is_interface_v<::std::decay_t<I>>, bool> = false>
-    INTERFACE_APPEND_LINE(interface__)
-    (I&& i)
+    // Whether name is one of names, which are stringized method names or member functions of interfaces.
+    // Methods may not share names with member functions, as calls would silently pick the member function.
+    constexpr bool is_named(const char* name, std::initializer_list<const char*> names) noexcept
     {
-        construct(::std::forward<I>(i));
+        for(auto r : names)
+        {
+            std::size_t k = 0;
+            while(name[k] && name[k] == r[k])
+                k++;
+            if(name[k] == r[k])
+                return true;
+        }
+        return false;
     }
+{{- if reflect}}
 
-    // SFINAE on whether argument is an interface.
-    // This is the conversion from any type to an interface.
-    template <typename T,
-              ::std::enable_if_t<!::interface_detail::is_interface_v<::std::decay_t<T>>, bool> = false>
-    INTERFACE_APPEND_LINE(interface__)
-    (T&& t)
+    // Entry of method_table, so that methods can be looked up by name, such as for scripting.
+    struct method_info
     {
-        using U = ::std::decay_t<T>;
-        static_assert(alignof(U) <= __STDCPP_DEFAULT_NEW_ALIGNMENT__, "Doesn't support overaligned type.");
-        static_assert(::std::is_constructible_v<U, const U&>, "Value semantics require the type be copy constructible.");
-
-        // Exception safe buffer allocation.
-        auto buf = ::std::unique_ptr<::std::byte[]>(new ::std::byte[sizeof(U)]);
-        _ptr = new (buf.get()) U{::std::forward<T>(t)};
-        buf.release();
-        _t = ::interface_detail::get_thunk<U>();
+        const char* name;
+        const char* signature;
+        std::size_t index;
+    };
 
-        // Constructs _vtable by name at compile time.
-        // erasure_fn is a unified interface to the method.
-        _vtable = {
-            ::interface_detail::erasure_fn<SIGNATURE0, METHOD_NAME0##_0_factory<U>>::value,
-        };
+    template<std::size_t M, std::size_t N>
+    constexpr std::array<method_info, M + N> concat_table(const std::array<method_info, M>& a, const std::array<method_info, N>& b)
+    {
+        std::array<method_info, M + N> t{};
+        for(std::size_t k = 0; k < M; k++)
+            t[k] = a[k];
+        for(std::size_t k = 0; k < N; k++)
+            t[M + k] = {b[k].name, b[k].signature, M + b[k].index};
+        return t;
     }
 
-    ~INTERFACE_APPEND_LINE(interface__)()
+    // Index of the first method named name, or N if there is none.
+    template<std::size_t N>
+    constexpr std::size_t find_method(const std::array<method_info, N>& t, std::string_view name) noexcept
     {
-        if(_ptr)
-            _t->destroy(_ptr);
-        delete[] reinterpret_cast<::std::byte*>(_ptr);
+        for(std::size_t k = 0; k < N; k++)
+            if(name == t[k].name)
+                return t[k].index;
+        return N;
     }
+{{- end}}
 
-    interface& operator=(const interface& other)
+    // Keys the getters of methods sharing a name.
+    // noexcept signatures also match requests for the signature without noexcept.
+    template<typename Signature>
+    struct signature_tag {};
+    template<typename Ret, typename... Args>
+    struct signature_tag<Ret(Args...) noexcept>
     {
-        auto tmp = other;
-        swap(*this, tmp);
-        return *this;
-    }
-    interface& operator=(interface&& other) noexcept
+        signature_tag() = default;
+        signature_tag(signature_tag<Ret(Args...)>) {}
+    };
+    template<typename Ret, typename... Args>
+    struct signature_tag<Ret(Args...) const noexcept>
     {
-        auto tmp = ::std::move(other);
-        swap(*this, tmp);
-        return *this;
-    }
-
-    template <typename... Args>
-    decltype(auto) METHOD_NAME0(Args&&... args)
+        signature_tag() = default;
+        signature_tag(signature_tag<Ret(Args...) const>) {}
+    };
+    template<typename Ret, typename... Args>
+    struct signature_tag<Ret(Args...) volatile noexcept>
     {
-        // Dispatches to type erased method call.
-        return get_##METHOD_NAME0(*this, ::interface_detail::interface_tag{})(
-            _ptr, ::std::forward<Args>(args)...);
-    }
-
-    // Fetches underlying type if thunk* matches, which serves as RTTI.
-    template<typename T>
-    friend T* target(interface&& i) noexcept
+        signature_tag() = default;
+        signature_tag(signature_tag<Ret(Args...) volatile>) {}
+    };
+    template<typename Ret, typename... Args>
+    struct signature_tag<Ret(Args..., ...) noexcept>
     {
-        if(i._t == ::interface_detail::get_thunk<T>())
-            return reinterpret_cast<T*>(i._ptr);
-        else
-            return nullptr;
-    }
-    template<typename T>
-    friend T* target(interface& i) noexcept
+        signature_tag() = default;
+        signature_tag(signature_tag<Ret(Args..., ...)>) {}
+    };
+    template<typename Ret, typename... Args>
+    struct signature_tag<Ret(Args..., ...) const noexcept>
     {
-        if(i._t == ::interface_detail::get_thunk<T>())
-            return reinterpret_cast<T*>(i._ptr);
-        else
-            return nullptr;
-    }
-    template<typename T>
-    friend const T* target(const interface& i) noexcept
+        signature_tag() = default;
+        signature_tag(signature_tag<Ret(Args..., ...) const>) {}
+    };
+    template<typename Ret, typename... Args>
+    struct signature_tag<Ret(Args...) const volatile noexcept>
     {
-        if(i._t == ::interface_detail::get_thunk<T>())
-            return reinterpret_cast<T*>(i._ptr);
-        else
-            return nullptr;
-    }
+        signature_tag() = default;
+        signature_tag(signature_tag<Ret(Args...) const volatile>) {}
+    };
+{{- if refs}}
+    template<typename Ret, typename... Args>
+    struct signature_tag<Ret(Args...) & noexcept>
+    {
+        signature_tag() = default;
+        signature_tag(signature_tag<Ret(Args...) &>) {}
+    };
+    template<typename Ret, typename... Args>
+    struct signature_tag<Ret(Args...) const& noexcept>
+    {
+        signature_tag() = default;
+        signature_tag(signature_tag<Ret(Args...) const&>) {}
+    };
+    template<typename Ret, typename... Args>
+    struct signature_tag<Ret(Args...) && noexcept>
+    {
+        signature_tag() = default;
+        signature_tag(signature_tag<Ret(Args...) &&>) {}
+    };
+    template<typename Ret, typename... Args>
+    struct signature_tag<Ret(Args...) const&& noexcept>
+    {
+        signature_tag() = default;
+        signature_tag(signature_tag<Ret(Args...) const&&>) {}
+    };
+{{- end}}
 
-    // Returns true if there is an underlying object.
-    explicit operator bool() const noexcept { return _ptr; }
+    // Methods returning a reference to interface_self return the interface, discarding the object's result.
+    // They are stored as returning void, so such methods also convert between interfaces.
+    template<typename Signature, typename Self>
+    struct fluent : std::false_type
+    {
+        using type = Signature;
+        using declared = Signature;
 
-    // Returns true iff both interfaces are empty or both references the same object.
-    template<typename I, std::enable_if_t<std::is_same_v<interface, std::decay_t<I>>, bool> = false>
-    bool operator==(I&& rhs) const noexcept
+        template<typename I, typename F, typename P, typename... Args>
+        static decltype(auto) call(I&, F* f, P p, Args&&... args)
+        {
+            return f(p, std::forward<Args>(args)...);
+        }
+    };
+
+    template<typename Ret, typename Signature, typename Declared>
+    struct fluent_impl : std::true_type
     {
-        if(!_ptr)
-            return !rhs._ptr;
-        if(::interface_detail::is_pointer_thunk(_t) && ::interface_detail::is_pointer_thunk(rhs._t))
-            return *reinterpret_cast<void**>(_ptr) == *reinterpret_cast<void**>(rhs._ptr);
-        return false;
-    }
-    template<typename I, std::enable_if_t<std::is_same_v<interface, std::decay_t<I>>, bool> = false>
-    bool operator!=(I&& rhs) const noexcept { return !(*this == rhs); }
+        using type = Signature;
+        // The signature with interface_self replaced by the interface.
+        using declared = Declared;
 
-    friend void swap(interface& x, interface& y) noexcept
+        template<typename I, typename F, typename P, typename... Args>
+        static Ret call(I& self, F* f, P p, Args&&... args)
+        {
+            f(p, std::forward<Args>(args)...);
+            return static_cast<Ret>(self);
+        }
+    };
+
+    template<typename Self, typename... Args, bool Noexcept>
+    struct fluent<::interface_self&(Args...) noexcept(Noexcept), Self>
+        : fluent_impl<Self&, void(Args...) noexcept(Noexcept), Self&(Args...) noexcept(Noexcept)> {};
+    template<typename Self, typename... Args, bool Noexcept>
+    struct fluent<const ::interface_self&(Args...) noexcept(Noexcept), Self>
+        : fluent_impl<const Self&, void(Args...) noexcept(Noexcept), const Self&(Args...) noexcept(Noexcept)> {};
+    template<typename Self, typename... Args, bool Noexcept>
+    struct fluent<::interface_self&(Args...) const noexcept(Noexcept), Self>
+        : fluent_impl<Self&, void(Args...) const noexcept(Noexcept), Self&(Args...) const noexcept(Noexcept)> {};
+    template<typename Self, typename... Args, bool Noexcept>
+    struct fluent<const ::interface_self&(Args...) const noexcept(Noexcept), Self>
+        : fluent_impl<const Self&, void(Args...) const noexcept(Noexcept), const Self&(Args...) const noexcept(Noexcept)> {};
+{{- if refs}}
+
+    // Ref-qualified methods return the interface as the same value category, so that chains of rvalues stay rvalues.
+    template<typename Self, typename... Args, bool Noexcept>
+    struct fluent<::interface_self&(Args...) & noexcept(Noexcept), Self>
+        : fluent_impl<Self&, void(Args...) & noexcept(Noexcept), Self&(Args...) & noexcept(Noexcept)> {};
+    template<typename Self, typename... Args, bool Noexcept>
+    struct fluent<const ::interface_self&(Args...) & noexcept(Noexcept), Self>
+        : fluent_impl<const Self&, void(Args...) & noexcept(Noexcept), const Self&(Args...) & noexcept(Noexcept)> {};
+    template<typename Self, typename... Args, bool Noexcept>
+    struct fluent<::interface_self&(Args...) const& noexcept(Noexcept), Self>
+        : fluent_impl<Self&, void(Args...) const& noexcept(Noexcept), Self&(Args...) const& noexcept(Noexcept)> {};
+    template<typename Self, typename... Args, bool Noexcept>
+    struct fluent<const ::interface_self&(Args...) const& noexcept(Noexcept), Self>
+        : fluent_impl<const Self&, void(Args...) const& noexcept(Noexcept), const Self&(Args...) const& noexcept(Noexcept)> {};
+    template<typename Self, typename... Args, bool Noexcept>
+    struct fluent<::interface_self&&(Args...) && noexcept(Noexcept), Self>
+        : fluent_impl<Self&&, void(Args...) && noexcept(Noexcept), Self&&(Args...) && noexcept(Noexcept)> {};
+    template<typename Self, typename... Args, bool Noexcept>
+    struct fluent<const ::interface_self&&(Args...) && noexcept(Noexcept), Self>
+        : fluent_impl<const Self&&, void(Args...) && noexcept(Noexcept), const Self&&(Args...) && noexcept(Noexcept)> {};
+    template<typename Self, typename... Args, bool Noexcept>
+    struct fluent<::interface_self&&(Args...) const&& noexcept(Noexcept), Self>
+        : fluent_impl<Self&&, void(Args...) const&& noexcept(Noexcept), Self&&(Args...) const&& noexcept(Noexcept)> {};
+    template<typename Self, typename... Args, bool Noexcept>
+    struct fluent<const ::interface_self&&(Args...) const&& noexcept(Noexcept), Self>
+        : fluent_impl<const Self&&, void(Args...) const&& noexcept(Noexcept), const Self&&(Args...) const&& noexcept(Noexcept)> {};
+{{- end}}
+
+    // Named last parameter of the type erased functions of C variadic methods, which va_start requires.
+    struct variadic_tag {};
+
+    // Calls f with the arguments of the parameters I, variadic_tag, then the trailing arguments J.
+    template<std::size_t... I, std::size_t... J, typename F, typename P, typename Tuple>
+    decltype(auto) call_variadic(std::index_sequence<I...>, std::index_sequence<J...>, F* f, P p, Tuple&& t)
     {
-        using ::std::swap;
-        swap(x._ptr, y._ptr);
-        swap(x._t, y._t);
-        swap(x._vtable, y._vtable);
+        return f(p, std::get<I>(std::move(t))..., variadic_tag{}, std::get<sizeof...(I) + J>(std::move(t))...);
     }
 
-  private:
-    template <typename T>
-    using erasure_fn_t = typename ::interface_detail::erasure_fn<T>::type;
-    using vtable_t = ::std::tuple<erasure_fn_t<SIGNATURE>*>;
+    // C variadic methods are called with their trailing arguments after a variadic_tag.
+    template<typename Signature, std::size_t N>
+    struct variadic_fluent : std::false_type
+    {
+        using type = Signature;
+        using declared = Signature;
 
-    void* _ptr = nullptr;
-    const ::interface_detail::thunk* _t = nullptr;
-    vtable_t _vtable = {};
-}
+        template<typename I, typename F, typename P, typename... Args>
+        static decltype(auto) call(I&, F* f, P p, Args&&... args)
+        {
+            static_assert(sizeof...(Args) >= N, "Too few arguments to the C variadic method.");
+            return call_variadic(std::make_index_sequence<N>{}, std::make_index_sequence<sizeof...(Args) - N>{},
+                f, p, std::forward_as_tuple(std::forward<Args>(args)...));
+        }
+    };
 
-#endif // INTERFACE_FOR_EXPOSITION_ONLY
+    template<typename Ret, typename... Args, bool Noexcept, typename Self>
+    struct fluent<Ret(Args..., ...) noexcept(Noexcept), Self>
+        : variadic_fluent<Ret(Args..., ...) noexcept(Noexcept), sizeof...(Args)> {};
+    template<typename Ret, typename... Args, bool Noexcept, typename Self>
+    struct fluent<Ret(Args..., ...) const noexcept(Noexcept), Self>
+        : variadic_fluent<Ret(Args..., ...) const noexcept(Noexcept), sizeof...(Args)> {};
 
-// The following is the actual implementaion for interface.
-`
+    // Names the empty state of interfaces, which they construct from, are assigned and compare equal to,
+    // like std::nullopt. Not default constructible, so that {} still means an empty interface.
+    struct none_t
+    {
+        explicit constexpr none_t(int) noexcept {}
+    };
 
-var interface_str = `{{define "macro args"}}
+    // Vtables hold every method as a slot of the same type, cast back to its erasure_fn type on access.
+    using slot = void(*)();
+
+    // Caches the vtables converted by one conversion between interfaces, keyed by the source vtable.
+    // Each conversion has its own cache as a function-local static, which only ever holds the types
+    // converted through it, so the list is short and the first node is usually the one looked for.
+    // Lookups are a lock-free walk of the list; a vtable built by two threads at once is kept once.
+    // Nodes are leaked so that vtables remain valid during static destruction.
+    template<typename Vtable>
+    class vtable_cache
+    {
+      public:
+        template<typename Make>
+        const Vtable* find(const void* key, Make make)
+        {
+            const node* first = head.load(std::memory_order_acquire);
+            if(auto found = find(first, nullptr, key))
+                return found;
+
+            auto n = new node{key, make(), first};
+            while(!head.compare_exchange_weak(n->next, n, std::memory_order_acq_rel, std::memory_order_acquire))
+            {
+                if(auto found = find(n->next, first, key))
+                {
+                    delete n;
+                    return found;
+                }
+                first = n->next;
+            }
+            return &n->vtable;
+        }
+
+      private:
+        struct node
+        {
+            const void* key;
+            Vtable vtable;
+            const node* next;
+        };
+
+        // Looks for key from first until last, which has been searched before.
+        static const Vtable* find(const node* first, const node* last, const void* key) noexcept
+        {
+            for(; first != last; first = first->next)
+                if(first->key == key)
+                    return &first->vtable;
+            return nullptr;
+        }
+
+        std::atomic<const node*> head{nullptr};
+    };
+
+    // erasure_fn is a traits class that handles void return types gracefully.
+    template<typename Signature, typename Factory = nothing>
+    struct erasure_fn;
+
+    // Const and volatile methods observe the stored object through a pointer with the same qualifiers.
+    template<typename Ret, typename Pointer, bool Noexcept, typename Factory, typename... Args>
+    struct erasure_fn_impl : Factory
+    {
+        using pointer = Pointer;
+        using type = Ret(pointer, Args...){{if boundary}} noexcept{{end}};
+        using return_type = Ret;
+        using function = std::function<Ret(Args...)>;
+        static constexpr bool is_const = std::is_const_v<std::remove_pointer_t<Pointer>>;
+        static constexpr bool is_noexcept = {{boundary}};
+
+        // Function type taking the parameters of the signature and returning I.
+        // Overload resolution among the selectors of methods sharing a name yields the index of the method.
+        // volatile doesn't take part, volatile methods are called like the others.
+        template<std::size_t I>
+        using selector = index<I>(std::conditional_t<is_const, const_tag, mutable_tag>, Args...);
+
+        // Selector of the method called without its last N arguments, ranked like default arguments.
+        template<std::size_t I, std::size_t N>
+        using omitting = typename omitted_selector<I, N, std::conditional_t<is_const, const_tag, mutable_tag>, std::tuple<Args...>>::type;
+
+        // Whether F provides a method callable with the signature.
+        template<typename F>
+        static constexpr bool implemented_by = std::is_invocable_r_v<Ret, factory_call<F>, pointer, Args...>;
+
+        // Parameters taken by value are constructed once by the caller and moved into the method,
+        // references are forwarded as they are, keeping the value category of the arguments.
+        // Interfaces passed by value are thus moved from rvalues, which never allocates.
+        static constexpr Ret value(pointer p, Args... args){{if boundary}} noexcept{{end}}
+        {
+{{- if boundary}}
+            return boundary<Ret>([&]() -> Ret {
+                if constexpr(std::is_void_v<Ret>)
+                    Factory::call(p, std::forward<Args>(args)...);
+                else
+                    return Factory::call(p, std::forward<Args>(args)...);
+            });
+{{- else}}
+            if constexpr(std::is_void_v<Ret>)
+                Factory::call(p, std::forward<Args>(args)...);
+            else
+                return Factory::call(p, std::forward<Args>(args)...);
+{{- end}}
+        }
+    };
+
+    // noexcept is carried through to the type erased function.
+    template<typename Ret, typename Pointer, typename Factory, typename... Args>
+    struct erasure_fn_impl<Ret, Pointer, true, Factory, Args...> : erasure_fn_impl<Ret, Pointer, false, Factory, Args...>
+    {
+        using base = erasure_fn_impl<Ret, Pointer, false, Factory, Args...>;
+        using pointer = typename base::pointer;
+        using type = Ret(pointer, Args...) noexcept;
+        static constexpr bool is_noexcept = true;
+        static constexpr Ret value(pointer p, Args... args) noexcept
+        {
+            return base::value(p, std::forward<Args>(args)...);
+        }
+    };
+
+    template<typename Ret, typename... Args, bool Noexcept, typename Factory>
+    struct erasure_fn<Ret(Args...) noexcept(Noexcept), Factory>
+        : erasure_fn_impl<Ret, void*, Noexcept, Factory, Args...> {};
+
+    template<typename Ret, typename... Args, bool Noexcept, typename Factory>
+    struct erasure_fn<Ret(Args...) const noexcept(Noexcept), Factory>
+        : erasure_fn_impl<Ret, const void*, Noexcept, Factory, Args...> {};
+
+    // For objects such as memory mapped registers.
+    template<typename Ret, typename... Args, bool Noexcept, typename Factory>
+    struct erasure_fn<Ret(Args...) volatile noexcept(Noexcept), Factory>
+        : erasure_fn_impl<Ret, volatile void*, Noexcept, Factory, Args...> {};
+
+    template<typename Ret, typename... Args, bool Noexcept, typename Factory>
+    struct erasure_fn<Ret(Args...) const volatile noexcept(Noexcept), Factory>
+        : erasure_fn_impl<Ret, const volatile void*, Noexcept, Factory, Args...> {};
+
+    // Unusable conversion target of interfaces whose single method has no std::function equivalent.
+    struct no_function
+    {
+        template<typename F>
+        no_function(F&&) {}
+    };
+
+    // C variadic arguments can't be forwarded, so C variadic methods call the method of the object with a
+    // std::va_list of the trailing arguments in their place, like vprintf for printf. The trailing
+    // arguments undergo the default argument promotions, and are read by the object with va_arg as usual.
+    // The va_list is only valid during the call, and noexcept isn't carried to the methods of the interface.
+    template<typename Impl, typename Pointer, typename... Args>
+    struct variadic : Impl
+    {
+        using type = typename Impl::return_type(Pointer, Args..., variadic_tag, ...) noexcept(Impl::is_noexcept);
+        using function = no_function;
+
+        template<std::size_t I>
+        using selector = index<I>(std::conditional_t<Impl::is_const, const_tag, mutable_tag>, Args..., ...);
+
+        static typename Impl::return_type value(Pointer p, Args... args, variadic_tag tag, ...) noexcept(Impl::is_noexcept)
+        {
+            struct list
+            {
+                std::va_list ap;
+                ~list() { va_end(ap); }
+            } l;
+            va_start(l.ap, tag);
+            return Impl::value(p, std::forward<Args>(args)..., l.ap);
+        }
+    };
+
+    template<typename Ret, typename... Args, bool Noexcept, typename Factory>
+    struct erasure_fn<Ret(Args..., ...) noexcept(Noexcept), Factory>
+        : variadic<erasure_fn<Ret(Args..., std::va_list) noexcept(Noexcept), Factory>, void*, Args...> {};
+
+    template<typename Ret, typename... Args, bool Noexcept, typename Factory>
+    struct erasure_fn<Ret(Args..., ...) const noexcept(Noexcept), Factory>
+        : variadic<erasure_fn<Ret(Args..., std::va_list) const noexcept(Noexcept), Factory>, const void*, Args...> {};
+{{- if refs}}
+
+    // Methods are called with a tag of the value category of the interface, which ref-qualified
+    // signatures bind like the object of member functions, so that only matching ones are selected.
+    template<typename Tag, typename Impl, typename... Args>
+    struct ref_qualified : Impl
+    {
+        template<std::size_t I>
+        using selector = index<I>(Tag, Args...);
+
+        template<std::size_t I, std::size_t N>
+        using omitting = typename omitted_selector<I, N, Tag, std::tuple<Args...>>::type;
+    };
+
+    template<typename Ret, typename... Args, bool Noexcept, typename Factory>
+    struct erasure_fn<Ret(Args...) & noexcept(Noexcept), Factory>
+        : ref_qualified<mutable_tag&, erasure_fn_impl<Ret, void*, Noexcept, Factory, Args...>, Args...> {};
+
+    template<typename Ret, typename... Args, bool Noexcept, typename Factory>
+    struct erasure_fn<Ret(Args...) const& noexcept(Noexcept), Factory>
+        : ref_qualified<const const_tag&, erasure_fn_impl<Ret, const void*, Noexcept, Factory, Args...>, Args...> {};
+
+    // Methods qualified && are called on the object as an rvalue.
+    template<typename Ret, typename... Args, bool Noexcept, typename Factory>
+    struct erasure_fn<Ret(Args...) && noexcept(Noexcept), Factory>
+        : ref_qualified<mutable_tag&&, erasure_fn_impl<Ret, rvalue*, Noexcept, Factory, Args...>, Args...> {};
+
+    template<typename Ret, typename... Args, bool Noexcept, typename Factory>
+    struct erasure_fn<Ret(Args...) const&& noexcept(Noexcept), Factory>
+        : ref_qualified<const const_tag&&, erasure_fn_impl<Ret, const rvalue*, Noexcept, Factory, Args...>, Args...> {};
+
+    // Methods qualified && can't be called repeatedly, so interfaces of one don't convert to std::function.
+    template<typename Sig>
+    inline constexpr bool is_rvalue_qualified_v = false;
+
+    template<typename Ret, typename... Args, bool Noexcept>
+    inline constexpr bool is_rvalue_qualified_v<Ret(Args...) && noexcept(Noexcept)> = true;
+
+    template<typename Ret, typename... Args, bool Noexcept>
+    inline constexpr bool is_rvalue_qualified_v<Ret(Args...) const&& noexcept(Noexcept)> = true;
+{{- end}}
+
+    // Counts the owners of a shared object, and the weak references outliving them.
+    // The object is destroyed with the last owner, and the block with the last weak reference.
+    struct shared_block
+    {
+        std::atomic<std::size_t> count{1};
+        // Weak references, plus one for all owners together.
+        std::atomic<std::size_t> weak{1};
+        void (*destroy)(shared_block* b) noexcept;
+        void (*free)(shared_block* b) noexcept;
+
+        void release() noexcept
+        {
+            if(count.fetch_sub(1, std::memory_order_acq_rel) == 1)
+            {
+                destroy(this);
+                release_weak();
+            }
+        }
+
+        void release_weak() noexcept
+        {
+            if(weak.fetch_sub(1, std::memory_order_acq_rel) == 1)
+                free(this);
+        }
+
+        // Adds an owner, unless the object is already destroyed.
+        bool acquire() noexcept
+        {
+            auto n = count.load(std::memory_order_relaxed);
+            while(n != 0)
+                if(count.compare_exchange_weak(n, n + 1, std::memory_order_acq_rel, std::memory_order_relaxed))
+                    return true;
+            return false;
+        }
+    };
+
+    // Shares a heap allocated T among its copies, destroying it with the last one.
+    // The object pointer comes first, so that it is accessed like a stored T*.
+    template<typename T>
+    struct shared_ref
+    {
+        // The object is destroyed separately from the block, which weak references keep alive.
+        struct block : shared_block
+        {
+            template<typename... Args>
+            explicit block(std::in_place_t, Args&&... args)
+                : shared_block{1, 1, &destroy_value, &free_block}, value{std::forward<Args>(args)...}
+            {
+            }
+            ~block() {}
+
+            union { T value; };
+
+            static void destroy_value(shared_block* b) noexcept { static_cast<block*>(b)->value.~T(); }
+            static void free_block(shared_block* b) noexcept { delete static_cast<block*>(b); }
+        };
+
+        T* object;
+        block* b;
+
+        template<typename... Args>
+        explicit shared_ref(std::in_place_t, Args&&... args)
+            : shared_ref{new block{std::in_place, std::forward<Args>(args)...}}
+        {
+        }
+        shared_ref(const shared_ref& other) noexcept : object{other.object}, b{other.b}
+        {
+            b->count.fetch_add(1, std::memory_order_relaxed);
+        }
+        shared_ref(shared_ref&& other) noexcept
+            : object{std::exchange(other.object, nullptr)}, b{std::exchange(other.b, nullptr)}
+        {
+        }
+        shared_ref& operator=(const shared_ref&) = delete;
+        ~shared_ref()
+        {
+            if(b)
+                b->release();
+        }
+
+        static shared_block* get_block(const void* p) noexcept { return static_cast<const shared_ref*>(p)->b; }
+
+        // Constructs a shared_ref at dst taking over an owner already added to b.
+        static void adopt(void* dst, shared_block* b) noexcept { new (dst) shared_ref{static_cast<block*>(b)}; }
+
+      private:
+        explicit shared_ref(block* b) noexcept : object{std::addressof(b->value)}, b{b} {}
+    };
+
+    template<typename T>
+    struct is_shared_ref : std::false_type {};
+    template<typename T>
+    struct is_shared_ref<shared_ref<T>> : std::true_type {};
+
+    // Shared interfaces store objects through shared_ref, pointers keep reference semantics.
+    template<typename T>
+    struct shared { using type = shared_ref<T>; };
+    template<typename T>
+    struct shared<T*> { using type = T*; };
+    template<typename T>
+    struct shared<shared_ref<T>> { using type = shared_ref<T>; };
+
+    template<typename T>
+    using shared_t = typename shared<T>::type;
+
+    // Unified interface to access stored object.
+    // Stored pointer signifies reference semantics.
+    template<typename T>
+    decltype(auto) as_object(void* p)
+    {
+        if constexpr(std::is_pointer_v<T>)
+            return **static_cast<T*>(p);
+        else if constexpr(is_shared_ref<T>::value)
+            return *static_cast<T*>(p)->object;
+        else
+            return *static_cast<T*>(p);
+    }
+
+    // Const access binds to a const object, including through stored pointers.
+    template<typename T>
+    decltype(auto) as_object(const void* p)
+    {
+        if constexpr(std::is_pointer_v<T>)
+            return static_cast<const std::remove_pointer_t<T>&>(**static_cast<const T*>(p));
+        else if constexpr(is_shared_ref<T>::value)
+            return static_cast<const std::remove_pointer_t<decltype(T::object)>&>(*static_cast<const T*>(p)->object);
+        else
+            return *static_cast<const T*>(p);
+    }
+
+    // volatile access binds to a volatile object, the storage itself is never volatile.
+    template<typename T>
+    decltype(auto) as_object(volatile void* p)
+    {
+        auto&& o = as_object<T>(const_cast<void*>(p));
+        return static_cast<volatile std::remove_reference_t<decltype(o)>&>(o);
+    }
+
+    template<typename T>
+    decltype(auto) as_object(const volatile void* p)
+    {
+        auto&& o = as_object<T>(const_cast<const void*>(p));
+        return static_cast<volatile std::remove_reference_t<decltype(o)>&>(o);
+    }
+{{- if refs}}
+
+    // Methods qualified && access the object as an rvalue.
+    template<typename T>
+    decltype(auto) as_object(rvalue* p)
+    {
+        return std::move(as_object<T>(static_cast<void*>(p)));
+    }
+
+    template<typename T>
+    decltype(auto) as_object(const rvalue* p)
+    {
+        return std::move(as_object<T>(static_cast<const void*>(p)));
+    }
+{{- end}}
+
+    // Size of the buffer within interface for small objects.
+    inline constexpr std::size_t sbo_size = {{sbo}};
+
+    // Small nothrow movable objects are stored within the interface, avoiding allocation.
+    // Nothrow move keeps move and swap of interfaces noexcept, whatever they hold, so that
+    // containers such as std::vector move interfaces rather than copy them when growing.
+    template<typename T>
+    inline constexpr bool is_inline_v = sizeof(T) <= sbo_size
+        && alignof(T) <= alignof(std::max_align_t)
+        && std::is_nothrow_move_constructible_v<T>;
+
+    template<std::size_t N = sbo_size>
+    struct inline_buffer
+    {
+        alignas(std::max_align_t) std::byte data[N];
+        std::byte* get() noexcept { return data; }
+    };
+    template<>
+    struct inline_buffer<0>
+    {
+        std::byte* get() noexcept { return nullptr; }
+    };
+{{- if abi}}
+
+    // Size of a class laid out as the given number of pointers, a buffer of the given size and alignment,
+    // more pointers, then an integer of tag bytes aligned to its size, as the generator lays out interfaces,
+    // for checking their layout with -abi-assert.
+    constexpr std::size_t abi_size(std::size_t before, std::size_t buffer, std::size_t align, std::size_t after, std::size_t tag) noexcept
+    {
+        constexpr std::size_t p = sizeof(void*);
+        auto round = [](std::size_t n, std::size_t a) { return (n + a - 1) / a * a; };
+        auto n = round(before * p, align) + round(buffer, align);
+        if(after)
+            n = round(n, p) + after * p;
+        if(tag)
+            n = round(n, tag) + tag;
+        return round(n, align > p ? align : p);
+    }
+
+    constexpr std::size_t abi_align(std::size_t align) noexcept
+    {
+        return align > alignof(void*) ? align : alignof(void*);
+    }
+{{- end}}
+
+    // Type erased special member functions.
+    struct thunk
+    {
+        void (*copy)(void* dst, const void* src) = nullptr;
+        // Only called for objects stored inline, which are nothrow movable, so that moving and
+        // swapping interfaces never throws. Heap objects are moved by their pointer instead.
+        void (*move)(void* dst, void* src) noexcept = nullptr;
+        void (*destroy)(void* p) noexcept = nullptr;
+        // Moves and destroys an object stored inline in one call, when moving interfaces.
+        void (*relocate)(void* dst, void* src) noexcept = nullptr;
+        std::size_t size = 0;
+        std::size_t align = 0;
+        bool inline_storage = false;
+
+        // Stored pointers signify reference semantics.
+        bool reference = false;
+        // The object is held through a pointer at the start of the storage, either a stored pointer
+        // or one owning the object.
+        bool indirect = false;
+
+        // For reference semantics, copies the referenced object into an owning pointer.
+        // Null if the referenced object isn't copy constructible.
+        const thunk* clone = nullptr;
+{{- if comparable}}
+
+        // Three-way comparison of two objects of the same type, null if they aren't comparable.
+        int (*compare)(const void* x, const void* y) = nullptr;
+{{- end}}
+{{- if equality}}
+
+        // Equality of two objects of the same type, null if they aren't equality comparable.
+        bool (*equals)(const void* x, const void* y) = nullptr;
+{{- end}}
+{{- if rtti}}
+        const std::type_info* type = &typeid(void);
+{{- end}}
+
+        // For objects held through a shared_ref, returns its block and constructs another shared_ref
+        // taking over an owner added to the block, for weak references.
+        shared_block* (*block)(const void* p) noexcept = nullptr;
+        void (*adopt)(void* dst, shared_block* b) noexcept = nullptr;
+    };
+
+{{- if comparable}}
+
+    // Compares through owning pointers when Owner is set.
+    // Stored pointers have reference semantics and are compared by interface instead.
+    template<typename T, bool Owner = false>
+    constexpr auto compare_fn() -> int (*)(const void*, const void*)
+    {
+        if constexpr(!std::is_pointer_v<T> && std::three_way_comparable<T, std::weak_ordering>)
+            return [](const void* x, const void* y) {
+                auto c = [](const void* p) -> const T& {
+                    if constexpr(Owner)
+                        return **static_cast<T* const*>(p);
+                    else
+                        return *static_cast<const T*>(p);
+                };
+                auto r = std::compare_three_way{}(c(x), c(y));
+                return r < 0 ? -1 : r > 0 ? 1 : 0;
+            };
+        else
+            return nullptr;
+    }
+
+{{- end}}
+
+    template<typename T, typename = void>
+    struct is_equality_comparable : std::false_type {};
+    template<typename T>
+    struct is_equality_comparable<T, std::void_t<decltype(bool(std::declval<const T&>() == std::declval<const T&>()))>> : std::true_type {};
+
+    // Types of the objects interfaces compare equal to when holding an equal one, other than interfaces and none.
+    template<typename T>
+    inline constexpr bool is_held_comparable_v =
+        !is_interface_v<T> && !std::is_same_v<T, none_t> && !std::is_array_v<T> && is_equality_comparable<T>::value;
+
+    // Returns true if the interface i holds a T equal to v.
+    template<typename I, typename T>
+    bool holds_equal(const I& i, const T& v)
+    {
+        auto p = i.template get_if<T>();
+        return p && bool(*p == v);
+    }
+{{- if equality}}
+
+    // Compares through owning pointers when Owner is set.
+    // Stored pointers have reference semantics and are compared by interface instead.
+    template<typename T, bool Owner = false>
+    constexpr auto equals_fn() -> bool (*)(const void*, const void*)
+    {
+        if constexpr(!std::is_pointer_v<T> && is_equality_comparable<T>::value)
+            return [](const void* x, const void* y) {
+                auto c = [](const void* p) -> const T& {
+                    if constexpr(Owner)
+                        return **static_cast<T* const*>(p);
+                    else
+                        return *static_cast<const T*>(p);
+                };
+                return bool(c(x) == c(y));
+            };
+        else
+            return nullptr;
+    }
+
+{{end -}}
+    // Relocates objects stored inline, which are nothrow movable. Trivially copyable objects, including
+    // pointers, are copied bytewise rather than through their constructors. Null for heap objects.
+    template<typename T>
+    constexpr auto relocate_fn() -> void (*)(void*, void*) noexcept
+    {
+        if constexpr(!is_inline_v<T>)
+            return nullptr;
+        else if constexpr(std::is_trivially_copyable_v<T>)
+            return [](void* dst, void* src) noexcept {
+                std::memcpy(dst, src, sizeof(T));
+            };
+        else
+            return [](void* dst, void* src) noexcept {
+                new (dst) T{std::move(*static_cast<T*>(src))};
+                static_cast<T*>(src)->~T();
+            };
+    }
+
+    // Owns a heap allocated T through a T*, so that interfaces use the same vtable as for T*.
+    template<typename T>
+    struct owner_storage
+    {
+        inline static constexpr thunk t = {
+            [](void* dst, const void* src) {
+                new (dst) T*{new T{**static_cast<T* const*>(src)}};
+            },
+            [](void* dst, void* src) noexcept {
+                new (dst) T*{std::exchange(*static_cast<T**>(src), nullptr)};
+            },
+            [](void* p) noexcept {
+                delete *static_cast<T**>(p);
+            },
+            relocate_fn<T*>(),
+            sizeof(T*),
+            alignof(T*),
+            is_inline_v<T*>,
+            false,
+            true,
+            nullptr,
+{{- if comparable}}
+            compare_fn<T, true>(),
+{{- end}}
+{{- if equality}}
+            equals_fn<T, true>(),
+{{- end}}
+{{- if rtti}}
+            &typeid(T),
+{{- end}}
+        };
+    };
+
+    // Objects referenced through const pointers are copied into mutable objects, which the copy owns.
+    // The vtable for const T* still applies, as only const methods are called through it.
+    template<typename T>
+    constexpr const thunk* clone_thunk()
+    {
+        if constexpr(std::is_pointer_v<T>)
+        {
+            using U = std::remove_const_t<std::remove_pointer_t<T>>;
+            if constexpr(std::is_object_v<U> && std::is_copy_constructible_v<U>)
+                return &owner_storage<U>::t;
+        }
+        return nullptr;
+    }
+
+    // Address of t acts as RTTI.
+    template<typename T, bool = std::is_constructible_v<T, const T&>>
+    struct thunk_storage
+    {
+        inline static constexpr thunk t = {
+            [](void* dst, const void* src) {
+                new (dst) T{*static_cast<const T*>(src)};
+            },
+            [](void* dst, void* src) noexcept {
+                new (dst) T{std::move(*static_cast<T*>(src))};
+            },
+            [](void* p) noexcept {
+                static_cast<T*>(p)->~T();
+            },
+            relocate_fn<T>(),
+            sizeof(T),
+            alignof(T),
+            is_inline_v<T>,
+            std::is_pointer_v<T>,
+            std::is_pointer_v<T>,
+            clone_thunk<T>(),
+{{- if comparable}}
+            compare_fn<T>(),
+{{- end}}
+{{- if equality}}
+            equals_fn<T>(),
+{{- end}}
+{{- if rtti}}
+            &typeid(T),
+{{- end}}
+        };
+    };
+    // Immovable types are never stored inline, so they are never moved.
+    template<typename T>
+    constexpr auto move_fn() -> void (*)(void*, void*) noexcept
+    {
+        if constexpr(std::is_move_constructible_v<T>)
+            return [](void* dst, void* src) noexcept {
+                new (dst) T{std::move(*static_cast<T*>(src))};
+            };
+        else
+            return nullptr;
+    }
+
+    // Move-only types, which copyable interfaces may hold by value as long as they aren't copied.
+    template<typename T>
+    struct thunk_storage<T, false>
+    {
+        inline static constexpr thunk t = {
+            [](void*, const void*) {
+                throw ::bad_interface_copy{};
+            },
+            move_fn<T>(),
+            [](void* p) noexcept {
+                static_cast<T*>(p)->~T();
+            },
+            relocate_fn<T>(),
+            sizeof(T),
+            alignof(T),
+            is_inline_v<T>,
+            false,
+            false,
+            nullptr,
+{{- if comparable}}
+            compare_fn<T>(),
+{{- end}}
+{{- if equality}}
+            equals_fn<T>(),
+{{- end}}
+{{- if rtti}}
+            &typeid(T),
+{{- end}}
+        };
+    };
+
+    // Copies the shared object into a shared_ref of its own, giving value semantics.
+    template<typename T>
+    struct shared_clone_storage
+    {
+        inline static constexpr thunk t = {
+            [](void* dst, const void* src) {
+                new (dst) shared_ref<T>{std::in_place, *static_cast<const shared_ref<T>*>(src)->object};
+            },
+            [](void* dst, void* src) noexcept {
+                new (dst) shared_ref<T>{std::move(*static_cast<shared_ref<T>*>(src))};
+            },
+            [](void* p) noexcept {
+                static_cast<shared_ref<T>*>(p)->~shared_ref();
+            },
+            relocate_fn<shared_ref<T>>(),
+            sizeof(shared_ref<T>),
+            alignof(shared_ref<T>),
+            is_inline_v<shared_ref<T>>,
+            false,
+            true,
+            nullptr,
+{{- if comparable}}
+            compare_fn<T, true>(),
+{{- end}}
+{{- if equality}}
+            equals_fn<T, true>(),
+{{- end}}
+{{- if rtti}}
+            &typeid(T),
+{{- end}}
+            &shared_ref<T>::get_block,
+            &shared_ref<T>::adopt,
+        };
+    };
+
+    template<typename T>
+    constexpr const thunk* shared_clone_thunk()
+    {
+        if constexpr(std::is_copy_constructible_v<T>)
+            return &shared_clone_storage<T>::t;
+        else
+            return nullptr;
+    }
+
+    // Copies share the object, so other interfaces holding a shared_ref have reference semantics.
+    template<typename T>
+    struct thunk_storage<shared_ref<T>, true>
+    {
+        inline static constexpr thunk t = {
+            [](void* dst, const void* src) {
+                new (dst) shared_ref<T>{*static_cast<const shared_ref<T>*>(src)};
+            },
+            [](void* dst, void* src) noexcept {
+                new (dst) shared_ref<T>{std::move(*static_cast<shared_ref<T>*>(src))};
+            },
+            [](void* p) noexcept {
+                static_cast<shared_ref<T>*>(p)->~shared_ref();
+            },
+            relocate_fn<shared_ref<T>>(),
+            sizeof(shared_ref<T>),
+            alignof(shared_ref<T>),
+            is_inline_v<shared_ref<T>>,
+            true,
+            true,
+            shared_clone_thunk<T>(),
+{{- if comparable}}
+            nullptr,
+{{- end}}
+{{- if equality}}
+            nullptr,
+{{- end}}
+{{- if rtti}}
+            &typeid(T),
+{{- end}}
+            &shared_ref<T>::get_block,
+            &shared_ref<T>::adopt,
+        };
+    };
+
+    template<typename T>
+    constexpr const thunk* get_thunk()
+    {
+        return &thunk_storage<T>::t;
+    }
+
+    // Pointer thunks are used to determine whether interface has reference semantics.
+    constexpr bool is_pointer_thunk(const thunk* t)
+    {
+        return t && t->reference;
+    }
+
+    // Objects held through a pointer are at the address it holds, others at p itself.
+    inline const void* underlying_address(const thunk* t, const void* p) noexcept
+    {
+        if(p && t->indirect)
+            return *static_cast<void* const*>(p);
+        return p;
+    }
+
+    // Returns the T stored at p, or nullptr if the thunk doesn't match.
+    // A stored T* refers to the T, copies made by clone hold T through an owning pointer,
+    // and shared interfaces hold T through a shared_ref.
+    // Like std::any_cast, T& is looked up as T, and cv-qualified T also matches the unqualified T,
+    // while const T only matches a stored const T* otherwise, which T doesn't.
+    template<typename T>
+    void* get_object(const thunk* t, void* p) noexcept
+    {
+        if constexpr(std::is_reference_v<T>)
+            return get_object<std::remove_reference_t<T>>(t, p);
+        else
+        {
+            if(!t)
+                return nullptr;
+            if(t == get_thunk<T>())
+                return p;
+            if constexpr(!std::is_same_v<T, std::remove_cv_t<T>>)
+                if(auto q = get_object<std::remove_cv_t<T>>(t, p))
+                    return q;
+            if constexpr(std::is_object_v<T>)
+                if(t == get_thunk<T*>() || t == clone_thunk<T*>()
+                   || t == get_thunk<shared_ref<T>>() || t == shared_clone_thunk<T>())
+                    return const_cast<std::remove_cv_t<T>*>(*static_cast<T**>(p));
+            return nullptr;
+        }
+    }
+
+    // Pointee of the pointers returned by target<T>, which doesn't form pointers to references.
+    template<typename T>
+    using target_t = std::remove_reference_t<T>;
+
+    // std::reference_wrapper is stored as a pointer to the referenced object, giving reference semantics.
+    template<typename T>
+    struct stored { using type = T; };
+    template<typename T>
+    struct stored<std::reference_wrapper<T>> { using type = T*; };
+
+    template<typename T>
+    using stored_t = typename stored<std::decay_t<T>>::type;
+
+    template<typename T>
+    decltype(auto) unwrap(T&& t) noexcept
+    {
+        if constexpr(std::is_pointer_v<stored_t<T>> && !std::is_pointer_v<std::decay_t<T>>)
+            return std::addressof(t.get());
+        else
+            return std::forward<T>(t);
+    }
+
+    // Heap storage for the object described by t, supporting overaligned types.
+{{- if pmr}}
+    // Allocated from mr, or from global new if it is null.
+{{- end}}
+    inline std::byte* allocate(const thunk* t{{if pmr}}, std::pmr::memory_resource* mr{{end}})
+    {
+{{- if pmr}}
+        if(mr)
+            return static_cast<std::byte*>(mr->allocate(t->size, t->align));
+{{- end}}
+        if(t->align > __STDCPP_DEFAULT_NEW_ALIGNMENT__)
+            return static_cast<std::byte*>(::operator new(t->size, std::align_val_t{t->align}));
+        return static_cast<std::byte*>(::operator new(t->size));
+    }
+
+    inline void deallocate(void* p, const thunk* t{{if pmr}}, std::pmr::memory_resource* mr{{end}}) noexcept
+    {
+{{- if pmr}}
+        if(mr)
+            mr->deallocate(p, t->size, t->align);
+        else
+{{- end}}
+        if(t->align > __STDCPP_DEFAULT_NEW_ALIGNMENT__)
+            ::operator delete(p, std::align_val_t{t->align});
+        else
+            ::operator delete(p);
+    }
+
+    // Exception safe buffer allocation.
+    struct deallocator
+    {
+        const thunk* t;
+{{- if pmr}}
+        std::pmr::memory_resource* mr;
+{{- end}}
+        void operator()(std::byte* p) const noexcept { deallocate(p, t{{if pmr}}, mr{{end}}); }
+    };
+    using buffer = std::unique_ptr<std::byte, deallocator>;
+
+    // Shared interfaces, which weak_interface refers to, are those able to adopt a shared_block.
+    template<typename I, typename = void>
+    struct is_shared_interface : std::false_type {};
+    template<typename I>
+    struct is_shared_interface<I, std::void_t<decltype(adopt(std::declval<I&>(), interface_tag{}, nullptr, nullptr, nullptr{{if pmr}}, nullptr{{end}}{{if tag}}, 0{{end}}))>>
+        : std::true_type {};
+}
+
+{{- if not memberTarget}}
+// For ADL purposes.
+{{export}}template<typename T, typename I>
+void target(I&&, ::{{detail}}::interface_tag);
+{{- end}}
+
+// Whether T is an interface, including compositions, for constraining user templates.
+{{export}}template<typename T>
+struct is_interface : ::std::bool_constant<::{{detail}}::is_interface_v<T>> {};
+
+{{export}}template<typename T>
+inline constexpr bool is_interface_v = is_interface<T>::value;
+
+// Constructs T from args directly within a new interface I, without copying or moving T.
+{{export}}template<typename I, typename T, typename... Args>
+I make_interface(Args&&... args)
+{
+    static_assert(::{{detail}}::is_interface_v<I>, "I must be an interface.");
+    return I(::std::in_place_type<T>, ::std::forward<Args>(args)...);
+}
+
+// Returns an interface I referring to obj through a pointer, rather than holding a copy of it.
+// Rvalues would dangle, and are rejected.
+{{export}}template<typename I, typename T>
+I make_ref(T& obj) noexcept
+{
+    static_assert(::{{detail}}::is_interface_v<I>, "I must be an interface.");
+    return I(::std::addressof(obj));
+}
+
+{{export}}template<typename I, typename T>
+void make_ref(const T&&) = delete;
+
+// Converts the interface i to Target, which must have a subset of its methods, as a named operation.
+// Each method of Target is looked up in i by name and signature, like the converting constructor,
+// so interfaces missing one fail to compile. The object is copied or moved as i is passed.
+{{export}}template<typename Target, typename I, ::std::enable_if_t<::{{detail}}::is_interface_v<::std::decay_t<I>>, bool> = false>
+Target interface_cast(I&& i)
+{
+    static_assert(::{{detail}}::is_interface_v<Target>, "Target must be an interface.");
+    return Target(::std::forward<I>(i));
+}
+
+// Calls v with the pointer returned by target for the first of Ts held by the interface i.
+// Returns whether any of Ts matched.
+{{export}}template<typename... Ts, typename I, typename V, ::std::enable_if_t<::{{detail}}::is_interface_v<::std::decay_t<I>>, bool> = false>
+bool visit(I&& i, V&& v)
+{
+    auto call = [&](auto* p) {
+        if(!p)
+            return false;
+        ::std::invoke(v, p);
+        return true;
+    };
+    return (call({{if memberTarget}}i.template target<Ts>(){{else}}target<Ts>(i){{end}}) || ...);
+}
+{{- if boundary}}
+
+// Category of the errors reported by interface_error for exceptions other than std::system_error,
+// reported by their code, and std::bad_alloc, reported as std::errc::not_enough_memory.
+{{export}}inline const ::std::error_category& interface_category() noexcept
+{
+    return ::{{detail}}::category();
+}
+
+// Returns the error of the last method of an interface called on this thread whose object threw,
+// and clears it. Such methods return a value initialized result instead of propagating the exception.
+{{export}}inline ::std::error_code interface_error() noexcept
+{
+    return ::std::exchange(::{{detail}}::last_error(), ::std::error_code{});
+}
+{{- end}}
+
+{{- if concepts}}
+// Satisfied by types providing every method of the interface I, which can then be converted to I.
+{{export}}template<typename T, typename I>
+concept implements = ::{{detail}}::is_interface_v<I> && I::template implemented_by<::{{detail}}::stored_t<T>>;
+
+{{end -}}
+// Hash consistent with operator==, which only compares referenced objects.
+// Reference semantics hash the referenced object's address, value semantics the stored object's.
+{{- if or comparable equality}}
+// Comparable objects are equal by value, so only their type is hashed.
+{{- end}}
+{{export}}struct interface_hash
+{
+    template<typename I, ::std::enable_if_t<::{{detail}}::is_interface_v<I>, bool> = false>
+    ::std::size_t operator()(const I& i) const noexcept
+    {
+        const void* p = fetch_ptr(i, ::{{detail}}::interface_tag{});
+        auto t = fetch_thunk(i, ::{{detail}}::interface_tag{});
+        if(p && ::{{detail}}::is_pointer_thunk(t))
+            p = *static_cast<void* const*>(p);
+{{- if comparable}}
+        else if(p && t->compare)
+            p = t;
+{{- end}}
+{{- if equality}}
+        else if(p && t->equals)
+            p = t;
+{{- end}}
+        return ::std::hash<const void*>{}(p);
+    }
+};
+
+{{- if not module}}
+
+// The anonymous interface types can only be matched by a constrained specialization.
+#if __cplusplus > 201703L
+template<typename I>
+    requires ::{{detail}}::is_interface_v<I>
+struct std::hash<I> : interface_hash {};
+#endif // __cplusplus
+{{- end}}
+
+// Interface with the methods of both A and B, holding a single object.
+// The object is owned by A, and B refers to it with its own vtable, sharing the thunk.
+// Methods present in both are ambiguous, and are called through a conversion to A or B instead.
+{{export}}template<typename A, typename B>
+class interface_compose : public A, public B
+{
+    static_assert(::{{detail}}::is_interface_v<A> && ::{{detail}}::is_interface_v<B>, "Only interfaces may be composed.");
+
+  public:
+    static constexpr ::std::size_t method_count = A::method_count + B::method_count;
+    template<::std::size_t I>
+    using method_signature = typename ::{{detail}}::composed_signature<A, B, I>::type;
+{{- if relocatable}}
+    using IsRelocatable = ::std::true_type;
+{{- end}}
+{{- if reflect}}
+    static constexpr auto method_table = ::{{detail}}::concat_table(A::method_table, B::method_table);
+    static constexpr ::std::size_t method_index(::std::string_view name) noexcept { return ::{{detail}}::find_method(method_table, name); }
+{{- end}}
+    static constexpr ::{{detail}}::none_t none{0};
+{{- if concepts}}
+
+    template<typename T>
+    static constexpr bool implemented_by = A::template implemented_by<T> && B::template implemented_by<T>;
+
+{{- end}}
+    interface_compose() = default;
+    interface_compose(::{{detail}}::none_t) noexcept {}
+    interface_compose(const interface_compose& other) : A(static_cast<const A&>(other)), B()
+    {
+        refer__(fetch_vtable(static_cast<const B&>(other), ::{{detail}}::interface_tag{}));
+    }
+    interface_compose(interface_compose&& other) noexcept : A(::std::move(static_cast<A&>(other)))
+    {
+        refer__(fetch_vtable(static_cast<const B&>(other), ::{{detail}}::interface_tag{}));
+        release(static_cast<B&>(other), ::{{detail}}::interface_tag{});
+    }
+
+    template<typename T,
+              ::std::enable_if_t<!::{{detail}}::is_interface_v<::std::decay_t<T>>
+                                 && !::{{detail}}::is_in_place_type<::std::decay_t<T>>::value, bool> = false>
+{{- if concepts}}
+        requires implemented_by<::{{detail}}::stored_t<T>>
+{{- end}}
+    interface_compose(T&& t) : interface_compose(::std::in_place_type<::{{detail}}::stored_t<T>>, ::{{detail}}::unwrap(::std::forward<T>(t)))
+    {
+    }
+{{- if tag}}
+
+    // The tag is held by A, like the object.
+    template<typename T,
+              ::std::enable_if_t<!::{{detail}}::is_interface_v<::std::decay_t<T>>
+                                 && !::{{detail}}::is_in_place_type<::std::decay_t<T>>::value, bool> = false>
+    interface_compose(T&& t, ::std::uint32_t tag) : interface_compose(::std::forward<T>(t))
+    {
+        store_tag(static_cast<A&>(*this), ::{{detail}}::interface_tag{}, tag);
+    }
+{{- end}}
+
+    // Shared interfaces store U through a shared_ref, which B's vtable must match.
+    template<typename U, typename... Args>
+    explicit interface_compose(::std::in_place_type_t<U> u, Args&&... args) : A(u, ::std::forward<Args>(args)...)
+    {
+        using shared = ::{{detail}}::shared_t<U>;
+        if(fetch_thunk(static_cast<const A&>(*this), ::{{detail}}::interface_tag{}) == ::{{detail}}::get_thunk<shared>())
+            refer__(vtable_for(static_cast<const B&>(*this), ::{{detail}}::interface_tag{}, ::std::in_place_type<shared>));
+        else
+            refer__(vtable_for(static_cast<const B&>(*this), ::{{detail}}::interface_tag{}, u));
+    }
+
+    // B's vtable is converted before A takes the object from i.
+    template<typename I,
+             ::std::enable_if_t<::{{detail}}::is_interface_v<::std::decay_t<I>>
+                                && !::std::is_same_v<::std::decay_t<I>, interface_compose>, bool> = false>
+    interface_compose(I&& i)
+        : interface_compose(i ? vtable_for(B{}, ::{{detail}}::interface_tag{}, i) : nullptr, ::std::forward<I>(i))
+    {
+    }
+
+    ~interface_compose() { release(static_cast<B&>(*this), ::{{detail}}::interface_tag{}); }
+
+    interface_compose& operator=(const interface_compose& other)
+    {
+        if(this == &other)
+            return *this;
+        auto tmp = other;
+        swap(*this, tmp);
+        return *this;
+    }
+    interface_compose& operator=(interface_compose&& other) noexcept
+    {
+        if(this == &other)
+            return *this;
+        auto tmp = ::std::move(other);
+        swap(*this, tmp);
+        return *this;
+    }
+    interface_compose& operator=(::{{detail}}::none_t) noexcept
+    {
+        reset();
+        return *this;
+    }
+
+    friend void swap(interface_compose& x, interface_compose& y) noexcept
+    {
+        auto vx = fetch_vtable(static_cast<const B&>(x), ::{{detail}}::interface_tag{});
+        auto vy = fetch_vtable(static_cast<const B&>(y), ::{{detail}}::interface_tag{});
+        swap(static_cast<A&>(x), static_cast<A&>(y));
+        x.refer__(vy);
+        y.refer__(vx);
+    }
+
+    explicit operator bool() const noexcept { return static_cast<bool>(static_cast<const A&>(*this)); }
+
+    interface_compose clone() const
+    {
+        return interface_compose(fetch_vtable(static_cast<const B&>(*this), ::{{detail}}::interface_tag{}),
+                                 static_cast<const A&>(*this).clone());
+    }
+{{- if rtti}}
+
+    using A::target_type;
+{{- end}}
+
+    void reset() noexcept
+    {
+        A::reset();
+        release(static_cast<B&>(*this), ::{{detail}}::interface_tag{});
+    }
+
+    // Constructs T before taking it, so B's vtable is set up like on construction.
+    template<typename T, typename... Args>
+    T& emplace(Args&&... args)
+    {
+        reset();
+        *this = interface_compose(::std::in_place_type<T>, ::std::forward<Args>(args)...);
+        return *this->template get_if<T>();
+    }
+
+    template<typename I, std::enable_if_t<std::is_same_v<interface_compose, std::decay_t<I>>, bool> = false>
+    bool operator==(I&& rhs) const noexcept(noexcept(::std::declval<const A&>() == ::std::declval<const A&>()))
+    {
+        return static_cast<const A&>(*this) == static_cast<const A&>(rhs);
+    }
+    template<typename I, std::enable_if_t<std::is_same_v<interface_compose, std::decay_t<I>>, bool> = false>
+    bool operator!=(I&& rhs) const noexcept(noexcept(::std::declval<const A&>() == ::std::declval<const A&>()))
+    {
+        return !(*this == rhs);
+    }
+    friend bool operator==(const interface_compose& i, ::{{detail}}::none_t) noexcept { return !i; }
+    friend bool operator==(::{{detail}}::none_t, const interface_compose& i) noexcept { return !i; }
+    friend bool operator!=(const interface_compose& i, ::{{detail}}::none_t) noexcept { return bool(i); }
+    friend bool operator!=(::{{detail}}::none_t, const interface_compose& i) noexcept { return bool(i); }
+
+    // Compares with objects of other types, matching exactly rather than the ambiguous operators of A and B.
+    template<typename T, std::enable_if_t<::{{detail}}::is_held_comparable_v<T>, bool> = false>
+    friend bool operator==(const interface_compose& i, const T& v) { return ::{{detail}}::holds_equal(i, v); }
+    template<typename T, std::enable_if_t<::{{detail}}::is_held_comparable_v<T>, bool> = false>
+    friend bool operator==(const T& v, const interface_compose& i) { return ::{{detail}}::holds_equal(i, v); }
+    template<typename T, std::enable_if_t<::{{detail}}::is_held_comparable_v<T>, bool> = false>
+    friend bool operator!=(const interface_compose& i, const T& v) { return !::{{detail}}::holds_equal(i, v); }
+    template<typename T, std::enable_if_t<::{{detail}}::is_held_comparable_v<T>, bool> = false>
+    friend bool operator!=(const T& v, const interface_compose& i) { return !::{{detail}}::holds_equal(i, v); }
+{{- if comparable}}
+    template<typename I, std::enable_if_t<std::is_same_v<interface_compose, std::decay_t<I>>, bool> = false>
+    ::std::weak_ordering operator<=>(I&& rhs) const
+    {
+        return static_cast<const A&>(*this) <=> static_cast<const A&>(rhs);
+    }
+{{- end}}
+
+{{- if memberTarget}}
+    using A::target;
+{{- else}}
+    template<typename T>
+    friend ::{{detail}}::target_t<T>* target(interface_compose&& i) noexcept { return target<T>(static_cast<A&>(i)); }
+    template<typename T>
+    friend ::{{detail}}::target_t<T>* target(interface_compose& i) noexcept { return target<T>(static_cast<A&>(i)); }
+    template<typename T>
+    friend const ::{{detail}}::target_t<T>* target(const interface_compose& i) noexcept { return target<T>(static_cast<const A&>(i)); }
+{{- end}}
+
+    using A::get_if;
+    using A::holds;
+    using A::underlying_address;
+    using A::has_reference_semantics;
+    using A::type_id;
+{{- if pmr}}
+    using A::resource;
+{{- end}}
+{{- if tag}}
+    using A::tag;
+{{- end}}
+
+    // Other interfaces convert from the object and thunk owned by A.
+    friend auto fetch_ptr(const interface_compose& i, ::{{detail}}::interface_tag)
+    {
+        return fetch_ptr(static_cast<const A&>(i), ::{{detail}}::interface_tag{});
+    }
+    friend auto&& fetch_thunk(const interface_compose& i, ::{{detail}}::interface_tag)
+    {
+        return fetch_thunk(static_cast<const A&>(i), ::{{detail}}::interface_tag{});
+    }
+    friend const void* fetch_vtable(const interface_compose& i, ::{{detail}}::interface_tag)
+    {
+        return fetch_vtable(static_cast<const A&>(i), ::{{detail}}::interface_tag{});
+    }
+{{- if pmr}}
+    friend auto fetch_resource(const interface_compose& i, ::{{detail}}::interface_tag)
+    {
+        return fetch_resource(static_cast<const A&>(i), ::{{detail}}::interface_tag{});
+    }
+{{- end}}
+{{- if tag}}
+    friend ::std::uint32_t fetch_tag(const interface_compose& i, ::{{detail}}::interface_tag)
+    {
+        return fetch_tag(static_cast<const A&>(i), ::{{detail}}::interface_tag{});
+    }
+{{- end}}
+    friend void release(interface_compose& i, ::{{detail}}::interface_tag) noexcept
+    {
+        release(static_cast<A&>(i), ::{{detail}}::interface_tag{});
+        release(static_cast<B&>(i), ::{{detail}}::interface_tag{});
+    }
+
+  private:
+    template<typename I>
+    interface_compose(const void* vtable, I&& i) : A(::std::forward<I>(i))
+    {
+        refer__(vtable);
+    }
+
+    // Points B to the object owned by A, through the given vtable of B.
+    void refer__(const void* vtable) noexcept
+    {
+        const A& a = *this;
+        if(a)
+            alias(static_cast<B&>(*this), ::{{detail}}::interface_tag{}, vtable,
+                  fetch_ptr(a, ::{{detail}}::interface_tag{}), fetch_thunk(a, ::{{detail}}::interface_tag{}));
+        else
+            release(static_cast<B&>(*this), ::{{detail}}::interface_tag{});
+    }
+};
+
+#define INTERFACE_COMPOSE(A, B) ::interface_compose<A, B>
+
+// For creating anonymous variables.
+#define INTERFACE_CONCAT_DIRECT(x, y) x##y
+#define INTERFACE_CONCAT(x, y) INTERFACE_CONCAT_DIRECT(x, y)
+#define INTERFACE_APPEND_LINE(x) INTERFACE_CONCAT(x, __LINE__)
+{{- if counter}}
+// Tells apart interfaces on the same line, such as those expanded from another macro.
+#ifdef __COUNTER__
+#define INTERFACE_APPEND_COUNTER(x) INTERFACE_CONCAT(INTERFACE_APPEND_LINE(x), INTERFACE_CONCAT(_, __COUNTER__))
+#else
+#define INTERFACE_APPEND_COUNTER(x) INTERFACE_APPEND_LINE(x)
+#endif
+{{- end}}
+{{- if not minimal}}
+
+#ifdef INTERFACE_FOR_EXPOSITION_ONLY
+// The following is used only as documentation to the implementation of interface.
+// SIGNATURE and METHOD_NAME are the parameters passed in by the user.
+// NAME is given to INTERFACE_DEFINE, and is INTERFACE_APPEND_LINE(interface__) for anonymous interfaces,
+// or INTERFACE_APPEND_COUNTER(interface__) with -unique=counter.
+// The multitudes of versions each have a different arity.
+
+// Inherits from interface_tag for type traits is_interface.
+class {{exported}}NAME : ::{{detail}}::interface_tag
+{
+    // Calls of methods named after member functions would silently pick the member function.
+    static_assert(!::{{detail}}::is_named(#METHOD_NAME0, {{reserved false}}), "method " #METHOD_NAME0 " has the name of a member function of the interface");
+
+    // Alias for both readability and for recursively defined functions:
+    // user may provide a function signature including interface.
+    using interface = NAME;
+
+    // Methods returning interface_self& are stored as returning void, see fluent.
+    template<typename S>
+    using signature_t = typename ::{{detail}}::fluent<S, interface>::type;
+
+    // Used in dispatching function call.
+    // Used in converting from one interface to another to bypass access level.
+    // interface_tag used to avoid namespace pollution, however improbable.
+    // signature_tag tells apart methods sharing a name.
+    friend auto get_##METHOD_NAME0(const interface& i, ::{{detail}}::interface_tag, ::{{detail}}::signature_tag<signature_t<SIGNATURE0>>)
+    {
+        return reinterpret_cast<erasure_fn_t<signature_t<SIGNATURE0>>*>((*i._vtable)[0]);
+    }
+
+    // Declared only, with the parameters of SIGNATURE0.
+    // Methods sharing a name overload their selectors, which pick the method to call.
+    static ::{{detail}}::erasure_fn<signature_t<SIGNATURE0>>::selector<0> METHOD_NAME0##_select;
+
+    // Factory for type erased method call
+    // Suffix used to avoid name collisions.
+    // p is a const void* for const methods, which binds the object as const.
+    // INTERFACE_FREE instead calls METHOD_NAME0(object, args...), found by ADL,
+    // and the methods of the interface are hidden friends rather than members.
+    // INTERFACE_DEFAULT calls DEFAULT0(object, args...) for objects not providing METHOD_NAME0.
+    // Objects with a data member METHOD_NAME0 that isn't invocable provide it as a method without
+    // parameters, returning the member as an lvalue, which can't be a bit-field.
+    template <typename T>
+    struct METHOD_NAME0##_0_factory
+    {
+        // Trailing return type lets implemented_by detect missing methods.
+        template <typename P, typename... Args>
+        static auto call(P* p, Args&&... args)
+            -> decltype(::{{detail}}::as_object<T>(p).METHOD_NAME0(::std::forward<Args>(args)...))
+        {
+            return ::{{detail}}::as_object<T>(p).METHOD_NAME0(::std::forward<Args>(args)...);
+        }
+    };
+
+    // Used in target.
+    // Used in converting from one interface to another to bypass access level.
+    // interface_tag used to avoid namespace pollution, however improbable.
+    friend auto fetch_ptr(const interface& i, ::{{detail}}::interface_tag) { return i._ptr; }
+
+    // Used in target.
+    // Used in converting from one interface to another to bypass access level.
+    // interface_tag used to avoid namespace pollution, however improbable.
+    friend auto&& fetch_thunk(const interface& i, ::{{detail}}::interface_tag)
+    {
+        return i._t;
+    }
+{{- if pmr}}
+
+    // Used in converting from one interface to another, which allocates from the same resource.
+    friend auto fetch_resource(const interface& i, ::{{detail}}::interface_tag) { return i._mr; }
+{{- end}}
+{{- if tag}}
+
+    // Used in converting from one interface to another, which keeps the tag, and by compositions.
+    friend ::std::uint32_t fetch_tag(const interface& i, ::{{detail}}::interface_tag) { return i._tag; }
+    friend void store_tag(interface& i, ::{{detail}}::interface_tag, ::std::uint32_t tag) noexcept { i._tag = tag; }
+{{- end}}
+
+    // Used in converting from one interface to another to identify the source vtable.
+    // interface_tag used to avoid namespace pollution, however improbable.
+    friend const void* fetch_vtable(const interface& i, ::{{detail}}::interface_tag)
+    {
+        return i._vtable;
+    }
+
+    // Used in converting from an expiring interface to take over its object.
+    // interface_tag used to avoid namespace pollution, however improbable.
+    friend void release(interface& i, ::{{detail}}::interface_tag) noexcept
+    {
+        i._ptr = nullptr;
+        i._t = nullptr;
+        i._vtable = nullptr;
+    }
+
+    // Used in interface_compose, whose second interface refers to the object owned by the first.
+    // Vtables are passed around as const void* since vtable_t differs between interfaces.
+    template<typename U>
+    friend const void* vtable_for(const interface&, ::{{detail}}::interface_tag, ::std::in_place_type_t<U>)
+    {
+        return make_vtable<U>();
+    }
+    template<typename I, ::std::enable_if_t<::{{detail}}::is_interface_v<I>, bool> = false>
+    friend const void* vtable_for(const interface&, ::{{detail}}::interface_tag, const I& i)
+    {
+        return convert_vtable(i);
+    }
+    friend void alias(interface& i, ::{{detail}}::interface_tag, const void* vtable, void* p, const ::{{detail}}::thunk* t) noexcept
+    {
+        i._ptr = p;
+        i._t = t;
+        i._vtable = static_cast<const vtable_t*>(vtable);
+    }
+
+    // Constructs the vtable by name at compile time, once for each type.
+    // erasure_fn is a unified interface to the method.
+    // The casts aren't constant expressions, but the vtable is still initialized statically.
+    template<typename U>
+    static const auto* make_vtable()
+    {
+        static const vtable_t vtable = {
+            reinterpret_cast<::{{detail}}::slot>(::{{detail}}::erasure_fn<signature_t<SIGNATURE0>, METHOD_NAME0##_0_factory<U>>::value),
+        };
+        return &vtable;
+    }
+
+    // Magic here. Constructs the vtable of another interface by name at compile time.
+    // This is the reason why we can't use polymorphic classes as in std::function.
+    // The vtable is shared between copies, and built once for each source vtable otherwise,
+    // cached without a lock by each conversion.
+    template<typename I>
+    static const auto* convert_vtable(const I& i)
+    {
+        if constexpr(::std::is_same_v<I, interface>)
+            return i._vtable;
+        else
+        {
+            static ::{{detail}}::vtable_cache<vtable_t> cache;
+            return cache.find(fetch_vtable(i, ::{{detail}}::interface_tag{}), [&] {
+                return vtable_t{
+                    reinterpret_cast<::{{detail}}::slot>(get_##METHOD_NAME0(i, ::{{detail}}::interface_tag{}, ::{{detail}}::signature_tag<signature_t<SIGNATURE0>>{})),
+                };
+            });
+        }
+    }
+
+    template<typename I>
+    void construct(I&& i)
+    {
+{{- if debugChecks}}
+        ::{{detail}}::check(fetch_ptr(i, ::{{detail}}::interface_tag{}), fetch_thunk(i, ::{{detail}}::interface_tag{}),
+                            fetch_vtable(i, ::{{detail}}::interface_tag{}));
+{{- end}}
+{{- if pmr}}
+        // Copies are allocated from the same resource, and taken over objects were allocated from it.
+        _mr = fetch_resource(i, ::{{detail}}::interface_tag{});
+{{- end}}
+{{- if tag}}
+        _tag = fetch_tag(i, ::{{detail}}::interface_tag{});
+{{- end}}
+        if(!i)
+            return;
+
+        auto p = fetch_ptr(i, ::{{detail}}::interface_tag{});
+        auto t = fetch_thunk(i, ::{{detail}}::interface_tag{});
+
+        // Heap objects of expiring interfaces are taken over rather than moved.
+        constexpr bool copying = ::std::is_lvalue_reference_v<I> || ::std::is_const_v<I>;
+        const bool steal = !copying && !t->inline_storage;
+        if(steal)
+            _ptr = p;
+        else
+        {
+            // Exception safe buffer allocation.
+            // Small objects are stored inline instead, decided by the thunk.
+            auto buf = ::{{detail}}::buffer{t->inline_storage ? nullptr : ::{{detail}}::allocate(t{{if pmr}}, _mr{{end}}), {t{{if pmr}}, _mr{{end}}}};
+            auto dst = buf ? buf.get() : _buf.get();
+
+            // Other constructor guarantees the two following calls are both valid.
+            if constexpr(copying)
+                t->copy(dst, p);
+            else
+                t->move(dst, p);
+
+            // Avoid [basic.life]/8 where original pointer cannot be used to refer to the newly
+            // constructed object.
+            _ptr = ::std::launder(dst);
+
+            buf.release();
+        }
+        _t = t;
+
+        _vtable = convert_vtable(i);
+
+        if constexpr(!copying)
+            if(steal)
+                release(i, ::{{detail}}::interface_tag{});
+    }
+
+    // Constructs U from args in place within this empty interface.
+    // The interface remains empty if construction throws.
+    // U need not be copy constructible, copying the interface then throws bad_interface_copy.
+    template <typename U, typename... Args>
+    void emplace__(Args&&... args)
+    {
+        if constexpr(::{{detail}}::is_inline_v<U>)
+            _ptr = new (_buf.get()) U{::std::forward<Args>(args)...};
+        else
+        {
+            // Exception safe buffer allocation.
+            auto t = ::{{detail}}::get_thunk<U>();
+            auto buf = ::{{detail}}::buffer{::{{detail}}::allocate(t{{if pmr}}, _mr{{end}}), {t{{if pmr}}, _mr{{end}}}};
+            _ptr = new (buf.get()) U{::std::forward<Args>(args)...};
+            buf.release();
+        }
+        _t = ::{{detail}}::get_thunk<U>();
+        _vtable = make_vtable<U>();
+    }
+
+    // Moves the contents of other into this empty interface, leaving other empty.
+    // Inline objects are relocated, which never throws. Heap objects are simply handed over.
+    void take(interface& other) noexcept
+    {
+{{- if pmr}}
+        // The resource is taken along with heap objects allocated from it.
+        _mr = other._mr;
+{{- end}}
+{{- if tag}}
+        _tag = other._tag;
+{{- end}}
+        if(!other._ptr)
+            return;
+
+        if(other._t->inline_storage)
+        {
+            other._t->relocate(_buf.get(), other._ptr);
+            _ptr = ::std::launder(_buf.get());
+        }
+        else
+            _ptr = other._ptr;
+
+        _t = other._t;
+        _vtable = other._vtable;
+        other._ptr = nullptr;
+        other._t = nullptr;
+        other._vtable = nullptr;
+    }
+
+  public:
+    // Number of methods, equal to ::std::tuple_size_v<vtable_t>. Spelled as a
+    // literal since vtable_t isn't declared yet.
+    static constexpr ::std::size_t method_count = 1;
+    // Signature of method I as given, for reflecting over the methods.
+    template<::std::size_t I>
+    using method_signature = typename ::{{detail}}::nth_signature<I, SIGNATURE0>::type;
+{{- if reflect}}
+    // Name, signature and index of each method, as written in the macro.
+    static constexpr ::std::array<::{{detail}}::method_info, 1> method_table = { { {{"{"}}#METHOD_NAME0, #SIGNATURE0, 0} } };
+    // Index of the first method named name, or method_count if there is none.
+    static constexpr ::std::size_t method_index(::std::string_view name) noexcept { return ::{{detail}}::find_method(method_table, name); }
+{{- end}}
+    // The empty state, i == interface::none reads as !i.
+    static constexpr ::{{detail}}::none_t none{0};
+{{- if relocatable}}
+
+    // Objects are never stored inline with -sbo=0, so nothing points into the interface, which may be
+    // moved by copying its bytes. Relocation aware containers, such as folly::fbvector, detect it by this.
+    using IsRelocatable = ::std::true_type;
+{{- end}}
+
+{{- if concepts}}
+    // Whether T provides every method, checked through the factories.
+    // Declared before the constructors constrained on it.
+    template<typename T>
+    static constexpr bool implemented_by =
+        ::{{detail}}::erasure_fn<signature_t<SIGNATURE0>>::template implemented_by<METHOD_NAME0##_0_factory<T>>;
+
+{{end -}}
+    NAME() = default;
+    NAME(::{{detail}}::none_t) noexcept {}
+    NAME(interface&& other) noexcept { take(other); }
+    // Deleted for INTERFACE_MOVE{{if moveOnly}} and INTERFACE{{end}}, along with copy assignment.
+    NAME(const interface& other) { construct(other); }
+
+    // SFINAE on whether argument is an interface.
+    // This is the converting constructor from other superset interfaces.
+    template <typename I,
+              ::std::enable_if_t<::{{detail}}::is_interface_v<::std::decay_t<I>>, bool> = false>
+    NAME
+    (I&& i)
+    {
+        construct(::std::forward<I>(i));
+    }
+
+    // SFINAE on whether argument is an interface.
+    // This is the conversion from any type to an interface.
+    template <typename T,
+              ::std::enable_if_t<!::{{detail}}::is_interface_v<::std::decay_t<T>>
+                                 && !::{{detail}}::is_in_place_type<::std::decay_t<T>>::value, bool> = false>
+{{- if concepts}}
+        requires implemented_by<::{{detail}}::stored_t<T>>
+{{- end}}
+    NAME
+    (T&& t) : NAME(::std::in_place_type<::{{detail}}::stored_t<T>>, ::{{detail}}::unwrap(::std::forward<T>(t)))
+    {
+    }
+
+    // Constructs U from args in place, used by make_interface.
+    template <typename U, typename... Args>
+    explicit NAME
+    (::std::in_place_type_t<U>, Args&&... args)
+    {
+        emplace__<U>(::std::forward<Args>(args)...);
+    }
+{{- if pmr}}
+
+    // Like the above, but allocating objects that aren't stored inline from mr.
+    // Copies and assigned interfaces allocate from the same resource.
+    template <typename T,
+              ::std::enable_if_t<!::{{detail}}::is_interface_v<::std::decay_t<T>>
+                                 && !::{{detail}}::is_in_place_type<::std::decay_t<T>>::value, bool> = false>
+    NAME
+    (::std::allocator_arg_t, ::std::pmr::memory_resource* mr, T&& t)
+        : NAME(::std::allocator_arg, mr, ::std::in_place_type<::{{detail}}::stored_t<T>>, ::{{detail}}::unwrap(::std::forward<T>(t)))
+    {
+    }
+    template <typename U, typename... Args>
+    NAME
+    (::std::allocator_arg_t, ::std::pmr::memory_resource* mr, ::std::in_place_type_t<U>, Args&&... args) : _mr{mr}
+    {
+        emplace<U>(::std::forward<Args>(args)...);
+    }
+{{- end}}
+{{- if tag}}
+
+    // Like the above, but with the tag returned by tag(), which copies and conversions keep.
+    template <typename T,
+              ::std::enable_if_t<!::{{detail}}::is_interface_v<::std::decay_t<T>>
+                                 && !::{{detail}}::is_in_place_type<::std::decay_t<T>>::value, bool> = false>
+    NAME
+    (T&& t, ::std::uint32_t tag) : NAME(::std::forward<T>(t))
+    {
+        _tag = tag;
+    }
+{{- end}}
+
+{{- if debugChecks}}
+    ~NAME()
+    {
+        reset();
+        ::{{detail}}::poison(_ptr, _t, _vtable);
+    }
+{{- else}}
+    ~NAME() { reset(); }
+{{- end}}
+
+    // Copies other before destroying the current object, which may own other.
+    // Self-assignment is a no-op rather than copying the object.
+    interface& operator=(const interface& other)
+    {
+{{- if debugChecks}}
+        ::{{detail}}::check(_ptr, _t, _vtable);
+        ::{{detail}}::check(other._ptr, other._t, other._vtable);
+{{- end}}
+        if(this == &other)
+            return *this;
+        auto tmp = other;
+        swap(*this, tmp);
+        return *this;
+    }
+    // Moves other out before destroying the current object, which may own other.
+    // Cheaper than swapping, so std::swap costs about the same as swap.
+    interface& operator=(interface&& other) noexcept
+    {
+        if(this == &other)
+            return *this;
+        auto tmp = ::std::move(other);
+        reset();
+        take(tmp);
+        return *this;
+    }
+    interface& operator=(::{{detail}}::none_t) noexcept
+    {
+        reset();
+        return *this;
+    }
+
+    // Enabled if overload resolution among methods of the same name selects this one.
+    // The distinct type of the last template parameter keeps methods sharing a name from redeclaring each other.
+    // noexcept if the signature is noexcept and the arguments convert without throwing.
+    template <typename... Args,
+              ::std::enable_if_t<decltype(METHOD_NAME0##_select(::{{detail}}::mutable_tag{}, ::std::declval<Args>()...))::value == 0,
+                                 ::{{detail}}::index<0>*> = nullptr>
+    decltype(auto) METHOD_NAME0(Args&&... args)
+        noexcept(::std::is_nothrow_invocable_v<erasure_fn_t<signature_t<SIGNATURE0>>*, void*, Args&&...>)
+    {
+{{- if debugChecks}}
+        debug_check__();
+{{- end}}
+{{- if checked}}
+        // There is no vtable to dispatch through.
+        if(!_ptr)
+            ::{{detail}}::nothing::empty();
+{{- end}}
+        // Dispatches to type erased method call.
+        return ::{{detail}}::fluent<SIGNATURE0, interface>::call(
+            *this, get_##METHOD_NAME0(*this, ::{{detail}}::interface_tag{}, ::{{detail}}::signature_tag<signature_t<SIGNATURE0>>{}),
+            _ptr, ::std::forward<Args>(args)...);
+    }
+
+    // Only const-qualified signatures may be selected on a const interface.
+    template <typename... Args,
+              ::std::enable_if_t<decltype(METHOD_NAME0##_select(::{{detail}}::const_tag{}, ::std::declval<Args>()...))::value == 0,
+                                 ::{{detail}}::index<0>*> = nullptr>
+    decltype(auto) METHOD_NAME0(Args&&... args) const
+        noexcept(::std::is_nothrow_invocable_v<erasure_fn_t<signature_t<SIGNATURE0>>*, const void*, Args&&...>)
+    {
+{{- if debugChecks}}
+        debug_check__();
+{{- end}}
+{{- if checked}}
+        if(!_ptr)
+            ::{{detail}}::nothing::empty();
+{{- end}}
+        return ::{{detail}}::fluent<SIGNATURE0, interface>::call(
+            *this, get_##METHOD_NAME0(*this, ::{{detail}}::interface_tag{}, ::{{detail}}::signature_tag<signature_t<SIGNATURE0>>{}),
+            static_cast<const void*>(_ptr), ::std::forward<Args>(args)...);
+    }
+
+    // Fetches underlying type if thunk* matches, which serves as RTTI.
+{{- if memberTarget}}
+    template<typename T>
+    ::{{detail}}::target_t<T>* target() noexcept
+    {
+        return static_cast<::{{detail}}::target_t<T>*>(::{{detail}}::get_object<T>(_t, _ptr));
+    }
+    template<typename T>
+    const ::{{detail}}::target_t<T>* target() const noexcept
+    {
+        return static_cast<const ::{{detail}}::target_t<T>*>(::{{detail}}::get_object<T>(_t, _ptr));
+    }
+{{- else}}
+    template<typename T>
+    friend ::{{detail}}::target_t<T>* target(interface&& i) noexcept
+    {
+        return static_cast<::{{detail}}::target_t<T>*>(::{{detail}}::get_object<T>(i._t, i._ptr));
+    }
+    template<typename T>
+    friend ::{{detail}}::target_t<T>* target(interface& i) noexcept
+    {
+        return static_cast<::{{detail}}::target_t<T>*>(::{{detail}}::get_object<T>(i._t, i._ptr));
+    }
+    template<typename T>
+    friend const ::{{detail}}::target_t<T>* target(const interface& i) noexcept
+    {
+        return static_cast<const ::{{detail}}::target_t<T>*>(::{{detail}}::get_object<T>(i._t, i._ptr));
+    }
+{{- end}}
+
+    // Member form of target.
+    template<typename T>
+    ::{{detail}}::target_t<T>* get_if() noexcept
+    {
+        return static_cast<::{{detail}}::target_t<T>*>(::{{detail}}::get_object<T>(_t, _ptr));
+    }
+    template<typename T>
+    const ::{{detail}}::target_t<T>* get_if() const noexcept
+    {
+        return static_cast<const ::{{detail}}::target_t<T>*>(::{{detail}}::get_object<T>(_t, _ptr));
+    }
+
+    // Returns true if target<T> would return the underlying object.
+    template<typename T>
+    bool holds() const noexcept
+    {
+        return get_if<T>();
+    }
+
+    // Address of the underlying object, which a stored pointer refers to, or null if empty.
+    // Doesn't need the type of the object, such as for identity checks, logging or C APIs.
+    const void* underlying_address() const noexcept
+    {
+        return ::{{detail}}::underlying_address(_t, _ptr);
+    }
+
+    // Returns true if the underlying object is referenced through a stored pointer rather than owned,
+    // so that it must outlive the interface, or be copied with clone to extend its lifetime.
+    bool has_reference_semantics() const noexcept
+    {
+        return ::{{detail}}::is_pointer_thunk(_t);
+    }
+
+    // Identifies the type of the held object by the address of its thunk, null if empty.
+    // Comparing it tells whether interfaces hold the same type, without RTTI.
+    const void* type_id() const noexcept { return _t; }
+{{- if pmr}}
+
+    // Returns the resource allocating objects that aren't stored inline, new_delete_resource for global new.
+    ::std::pmr::memory_resource* resource() const noexcept
+    {
+        return _mr ? _mr : ::std::pmr::new_delete_resource();
+    }
+{{- end}}
+{{- if tag}}
+
+    // Returns the tag given on construction, 0 if none was.
+    ::std::uint32_t tag() const noexcept { return _tag; }
+{{- end}}
+
+    // Returns true if there is an underlying object.
+    explicit operator bool() const noexcept { return _ptr; }
+
+    // Only for interfaces of a single method.
+    // Returns a std::function calling the method of a copy of the interface.
+    operator typename ::{{detail}}::erasure_fn<typename ::{{detail}}::fluent<SIGNATURE0, interface>::declared>::function() const
+    {
+        return [i = *this](auto&&... args) mutable -> decltype(auto) {
+            return i.METHOD_NAME0(::std::forward<decltype(args)>(args)...);
+        };
+    }
+
+    // Returns an interface with value semantics.
+    // References are followed and the referenced object copied onto the heap.
+    // Empty if the referenced object isn't copy constructible.
+    // Left out if a method is named clone, which calls would otherwise pick only on const interfaces.
+    template<bool B = !::{{detail}}::is_named("clone", {#METHOD_NAME0}), ::std::enable_if_t<B, bool> = false>
+    interface clone() const
+    {
+        if(!::{{detail}}::is_pointer_thunk(_t))
+            return *this;
+
+        interface i;
+        auto t = _t->clone;
+        if(!t)
+            return i;
+        {{- if pmr}}
+        i._mr = _mr;
+{{- end}}
+{{- if tag}}
+        i._tag = _tag;
+{{- end}}
+        auto buf = ::{{detail}}::buffer{t->inline_storage ? nullptr : ::{{detail}}::allocate(t{{if pmr}}, i._mr{{end}}), {t{{if pmr}}, i._mr{{end}}}};
+        auto dst = buf ? buf.get() : i._buf.get();
+        t->copy(dst, _ptr);
+        i._ptr = ::std::launder(dst);
+        buf.release();
+        i._t = t;
+        i._vtable = _vtable;
+        return i;
+    }
+{{- if rtti}}
+
+    // Returns the type of the underlying object, void if empty.
+    const ::std::type_info& target_type() const noexcept
+    {
+        return _t ? *_t->type : typeid(void);
+    }
+{{- end}}
+
+    // Destroys the underlying object, leaving the interface empty.
+    void reset() noexcept
+    {
+        if(!_ptr)
+            return;
+        _t->destroy(_ptr);
+        if(!_t->inline_storage)
+            ::{{detail}}::deallocate(_ptr, _t{{if pmr}}, _mr{{end}});
+        _ptr = nullptr;
+        _t = nullptr;
+        _vtable = nullptr;
+    }
+
+    // Replaces the underlying object with T constructed from args in place, like std::any::emplace.
+    // Leaves the interface empty if construction throws.
+    template <typename T, typename... Args>
+    T& emplace(Args&&... args)
+    {
+        reset();
+        emplace__<T>(::std::forward<Args>(args)...);
+        return *get_if<T>();
+    }
+
+{{- if comparable}}
+    // Objects of the same type are compared by value, otherwise the order of types is arbitrary.
+    // Reference semantics compare the addresses of referenced objects.
+    // Values that aren't comparable are only equal to themselves.
+    template<typename I, std::enable_if_t<std::is_same_v<interface, std::decay_t<I>>, bool> = false>
+    ::std::weak_ordering operator<=>(I&& rhs) const
+    {
+        if(_t != rhs._t)
+            return ::std::compare_three_way{}(_t, rhs._t);
+        if(!_ptr)
+            return ::std::weak_ordering::equivalent;
+        if(::{{detail}}::is_pointer_thunk(_t))
+            return ::std::compare_three_way{}(*reinterpret_cast<void**>(_ptr), *reinterpret_cast<void**>(rhs._ptr));
+        if(_t->compare)
+            return _t->compare(_ptr, rhs._ptr) <=> 0;
+        return ::std::compare_three_way{}(_ptr, rhs._ptr);
+    }
+    template<typename I, std::enable_if_t<std::is_same_v<interface, std::decay_t<I>>, bool> = false>
+    bool operator==(I&& rhs) const { return (*this <=> rhs) == 0; }
+    template<typename I, std::enable_if_t<std::is_same_v<interface, std::decay_t<I>>, bool> = false>
+    bool operator!=(I&& rhs) const { return !(*this == rhs); }
+{{- else}}
+    // Returns true iff both interfaces are empty or both references the same object.
+{{- if equality}}
+    // Objects of the same type are also compared by value with their own operator==, which may throw.
+{{- end}}
+    template<typename I, std::enable_if_t<std::is_same_v<interface, std::decay_t<I>>, bool> = false>
+    bool operator==(I&& rhs) const{{if not equality}} noexcept{{end}}
+    {
+        if(!_ptr)
+            return !rhs._ptr;
+        if(::{{detail}}::is_pointer_thunk(_t) && ::{{detail}}::is_pointer_thunk(rhs._t))
+            return *reinterpret_cast<void**>(_ptr) == *reinterpret_cast<void**>(rhs._ptr);
+{{- if equality}}
+        if(_t == rhs._t && _t->equals)
+            return _t->equals(_ptr, rhs._ptr);
+{{- end}}
+        return false;
+    }
+    template<typename I, std::enable_if_t<std::is_same_v<interface, std::decay_t<I>>, bool> = false>
+    bool operator!=(I&& rhs) const{{if not equality}} noexcept{{end}} { return !(*this == rhs); }
+{{- end}}
+
+    // Tests for the empty state.
+    friend bool operator==(const interface& i, ::{{detail}}::none_t) noexcept { return !i; }
+    friend bool operator==(::{{detail}}::none_t, const interface& i) noexcept { return !i; }
+    friend bool operator!=(const interface& i, ::{{detail}}::none_t) noexcept { return bool(i); }
+    friend bool operator!=(::{{detail}}::none_t, const interface& i) noexcept { return bool(i); }
+
+    // Compares with objects of other types, equal if the underlying object is a T equal to v.
+    template<typename T, std::enable_if_t<::{{detail}}::is_held_comparable_v<T>, bool> = false>
+    friend bool operator==(const interface& i, const T& v) { return ::{{detail}}::holds_equal(i, v); }
+    template<typename T, std::enable_if_t<::{{detail}}::is_held_comparable_v<T>, bool> = false>
+    friend bool operator==(const T& v, const interface& i) { return ::{{detail}}::holds_equal(i, v); }
+    template<typename T, std::enable_if_t<::{{detail}}::is_held_comparable_v<T>, bool> = false>
+    friend bool operator!=(const interface& i, const T& v) { return !::{{detail}}::holds_equal(i, v); }
+    template<typename T, std::enable_if_t<::{{detail}}::is_held_comparable_v<T>, bool> = false>
+    friend bool operator!=(const T& v, const interface& i) { return !::{{detail}}::holds_equal(i, v); }
+
+    // Inline objects can't be swapped by pointer, so contents are moved through a temporary.
+    friend void swap(interface& x, interface& y) noexcept
+    {
+        interface tmp = ::std::move(x);
+        x.take(y);
+        y.take(tmp);
+    }
+
+  private:
+    template <typename T>
+    using erasure_fn_t = typename ::{{detail}}::erasure_fn<T>::type;
+    // One slot per method, indexed like SIGNATURE and METHOD_NAME.
+    using vtable_t = ::std::array<::{{detail}}::slot, 1>;
+{{- if debugChecks}}
+
+    // Checks the invariant before calling a method.
+    void debug_check__() const noexcept { ::{{detail}}::{{if checked}}check{{else}}check_held{{end}}(_ptr, _t, _vtable); }
+{{- end}}
+
+    // Points to the object. Objects on the heap always start at their allocation, overaligned ones
+    // are allocated with their alignment rather than offset within it, so _ptr is also what
+    // deallocate takes and no separate allocation pointer is needed.
+    void* _ptr = nullptr;
+
+    // The thunk and the vtable are kept apart rather than in one descriptor per type, as they vary
+    // independently: clone swaps the thunk for that of the copy and keeps the vtable, and converting
+    // between interfaces builds another vtable and keeps the thunk. A combined descriptor would have
+    // to be looked up for each pair at run time, in the cache of convert_vtable, to save one pointer.
+    const ::{{detail}}::thunk* _t = nullptr;
+
+    // Points to a vtable shared by all interfaces holding the same type, like a C++ vtable.
+    const vtable_t* _vtable = nullptr;
+
+    // Holds the object if the thunk has inline_storage, otherwise it is on the heap.
+    ::{{detail}}::inline_buffer<> _buf;
+{{- if pmr}}
+
+    // Allocates the object if it is on the heap, global new if null.
+    ::std::pmr::memory_resource* _mr = nullptr;
+{{- end}}
+{{- if tag}}
+
+    // Classifies the object for the user, without a method of its own.
+    ::std::uint32_t _tag = 0;
+{{- end}}
+}
+
+#endif // INTERFACE_FOR_EXPOSITION_ONLY
+{{- end}}
+
+// The following is the actual implementaion for interface.
+
+`
+
+var interface_str = `{{define "macro args"}}
+    {{- range $k, $v := . -}}
+        {{if $k}}, {{end -}}
+        {{$v.Params -}}
+    {{else -}}
+        ...
+    {{- end}}
+{{- end}}
+{{- define "no methods"}}
+    {{- if not .Methods}}
+    static_assert(sizeof(#__VA_ARGS__) == 1, "{{.Macro}} takes {{if .Default}}a signature, a name and a default{{else}}a signature and a name{{end}} for each method.");\
+    {{- end}}
+{{- end}}
+{{- define "named methods"}}
+    {{- if not .Callable}}
+    {{- range $k, $v := .Methods}}{{if not $v.Free}}{{if $k}}, {{end}}#{{$v.Name}}{{end}}{{end}}
+    {{- end}}
+{{- end}}
+{{- define "reserved names"}}
+    {{- if not .Callable}}
+    {{- $reserved := reserved .View}}
+    {{- range .Methods}}
+    {{- if not .Free}}
+    static_assert(!::{{detail}}::is_named(#{{.Name}}, {{$reserved}}), "method " #{{.Name}} " has the name of a member function of the interface");\
+    {{- end}}
+    {{- end}}
+    {{- end}}
+{{- end}}
+{{- define "default args"}}
+    {{- range $k, $v := . -}}
+        {{if $k}}, {{end -}}
+        {{$v -}}
+    {{end}}
+{{- end}}
+{{- define "debug check"}}
+    {{- if debugChecks}}
+        ::{{detail}}::check({{.}}_ptr, {{.}}_t, {{.}}_vtable);\
+    {{- end}}
+{{- end}}
+{{- define "debug check call"}}
+    {{- if debugChecks}}
+        {{.}}debug_check__();\
+    {{- end}}
+{{- end}}
+{{- define "implemented by"}}
+    {{- range $k, $v := . -}}
+        {{if $k}} && {{end -}}
+        ::{{detail}}::erasure_fn<signature_t<SIGNATURE{{$v.Index}}>>::template implemented_by<{{$v.Factory}}<T__>>
+    {{- else -}}
+        true
+    {{- end}}
+{{- end}}
+{{- define "reflect"}}
+    {{- if reflect}}
+    static constexpr ::std::array<::{{detail}}::method_info, {{len .Methods}}> method_table = { {
+        {{- range $k, $v := .Methods}}{{if $k}}, {{end}}{{"{"}}{{if $.Callable}}"operator()"{{else}}#{{.Name}}{{end}}, #SIGNATURE{{.Index}}, {{.Index}}}{{end -}}
+    } };\
+    static constexpr ::std::size_t method_index(::std::string_view name) noexcept { return ::{{detail}}::find_method(method_table, name); }\
+    {{- end}}
+{{- end}}
+{{- define "abi layout"}}3, {{if sbo}}{{sbo}}{{else}}1{{end}}, {{template "abi align"}}, {{if pmr}}1{{else}}0{{end}}, {{if tag}}sizeof(::std::uint32_t){{else}}0{{end}}{{end}}
+{{- define "abi align"}}{{if sbo}}alignof(::std::max_align_t){{else}}1{{end}}{{end}}
+{{- define "call"}}
+    {{- if .Free -}}
+        {{.Name}}(::{{detail}}::as_object<T__>(p), ::std::forward<Args__>(as)...)
+    {{- else -}}
+        ::{{detail}}::as_object<T__>(p){{.Call}}(::std::forward<Args__>(as)...)
+    {{- end}}
+{{- end}}
+{{- define "method decls"}}
+    {{- range .Methods}}
+    friend auto {{.Getter}}(const interface& i, ::{{detail}}::interface_tag, ::{{detail}}::signature_tag<signature_t<SIGNATURE{{.Index}}>>)\
+    {\
+        return reinterpret_cast<erasure_fn_t<signature_t<SIGNATURE{{.Index}}>>*>((*i._vtable)[{{.Index}}]);\
+    }\
+\
+    static ::{{detail}}::erasure_fn<signature_t<SIGNATURE{{.Index}}>>::selector<{{.Index}}> {{.Selector}};\
+\
+    template<typename T__>\
+    struct {{.Factory}}\
+    {\
+        {{- if .Default}}
+        template<typename P__, typename... Args__>\
+        static auto method(P__* p, Args__&&... as)\
+            -> decltype({{template "call" .}})\
+        {\
+            return {{template "call" .}};\
+        }\
+        {{- if .Call}}
+        template<typename P__>\
+        static auto method(P__* p) -> decltype(::{{detail}}::field(&::{{detail}}::as_object<T__>(p){{.Call}}))\
+        {\
+            return ::{{detail}}::field(&::{{detail}}::as_object<T__>(p){{.Call}});\
+        }\
+        {{- end}}
+        template<typename P__, typename... Args__>\
+        static decltype(auto) call(P__* p, Args__&&... as)\
+        {\
+            if constexpr(::std::is_invocable_v<::{{detail}}::method_call<{{.Factory}}>, P__*, Args__&&...>)\
+                return method(p, ::std::forward<Args__>(as)...);\
+            else\
+                return ({{.Default}})(::{{detail}}::as_object<T__>(p), ::std::forward<Args__>(as)...);\
+        }\
+        {{- else}}
+        template<typename P__, typename... Args__>\
+        static auto call(P__* p, Args__&&... as)\
+            -> decltype({{template "call" .}})\
+        {\
+            return {{template "call" .}};\
+        }\
+        {{- if .Call}}
+        template<typename P__>\
+        static auto call(P__* p) -> decltype(::{{detail}}::field(&::{{detail}}::as_object<T__>(p){{.Call}}))\
+        {\
+            return ::{{detail}}::field(&::{{detail}}::as_object<T__>(p){{.Call}});\
+        }\
+        {{- end}}
+        {{- end}}
+    };\
+    {{- end}}
+{{- end}}
+{{- define "vtables"}}
+    template<typename U__>\
+    static const auto* make_vtable()\
+    {\
+        static const vtable_t vtable = {\
+            {{- range .Methods}}
+            reinterpret_cast<::{{detail}}::slot>(::{{detail}}::erasure_fn<signature_t<SIGNATURE{{.Index}}>, {{.Factory}}<U__>>::value),\
+            {{- end}}
+        };\
+        return &vtable;\
+    }\
+\
+    template<typename I__>\
+    static const auto* convert_vtable(const I__& i)\
+    {\
+        if constexpr(::std::is_same_v<I__, interface>)\
+            return i._vtable;\
+        else\
+        {\
+            static ::{{detail}}::vtable_cache<vtable_t> cache;\
+            return cache.find(fetch_vtable(i, ::{{detail}}::interface_tag{}), [&] {\
+                return vtable_t{\
+                    {{- range .Methods}}
+                    reinterpret_cast<::{{detail}}::slot>({{.Getter}}(i, ::{{detail}}::interface_tag{}, ::{{detail}}::signature_tag<signature_t<SIGNATURE{{.Index}}>>{})),\
+                    {{- end}}
+                };\
+            });\
+        }\
+    }\
+{{- end}}
+{{- define "method defs"}}
+    {{- range .Methods}}
+    {{- if doc}}
+    /** @brief Calls {{if .Free}}{{.Name}}(object, args...){{else if .Call}}{{.Name}} of the held object{{else}}the held object{{end}} as SIGNATURE{{.Index}}. */\
+    {{- end}}
+    {{- if .Free}}
+    template<typename... Args__, ::std::enable_if_t<decltype(interface::{{.Selector}}(::{{detail}}::mutable_tag{}, ::std::declval<Args__>()...))::value == {{.Index}}, ::{{detail}}::index<{{.Index}}>*> = nullptr>\
+    friend decltype(auto) {{.Name}}(interface& i, Args__&&... as) noexcept(::std::is_nothrow_invocable_v<typename ::{{detail}}::erasure_fn<signature_t<SIGNATURE{{.Index}}>>::type*, void*, Args__&&...>)\
+    {\
+        {{- template "debug check call" "i."}}
+        {{- if checked}}
+        if(!i._ptr)\
+            ::{{detail}}::nothing::empty();\
+        {{- end}}
+        return ::{{detail}}::fluent<SIGNATURE{{.Index}}, interface>::call(i, {{.Getter}}(i, ::{{detail}}::interface_tag{}, ::{{detail}}::signature_tag<signature_t<SIGNATURE{{.Index}}>>{}), i._ptr, ::std::forward<Args__>(as)...);\
+    }\
+    template<typename... Args__, ::std::enable_if_t<decltype(interface::{{.Selector}}(::{{detail}}::mutable_tag{}, ::std::declval<Args__>()...))::value == {{.Index}}, ::{{detail}}::index<{{.Index}}>*> = nullptr>\
+    friend decltype(auto) {{.Name}}(interface&& i, Args__&&... as) noexcept(::std::is_nothrow_invocable_v<typename ::{{detail}}::erasure_fn<signature_t<SIGNATURE{{.Index}}>>::type*, void*, Args__&&...>)\
+    {\
+        {{- template "debug check call" "i."}}
+        {{- if checked}}
+        if(!i._ptr)\
+            ::{{detail}}::nothing::empty();\
+        {{- end}}
+        return ::{{detail}}::fluent<SIGNATURE{{.Index}}, interface>::call(i, {{.Getter}}(i, ::{{detail}}::interface_tag{}, ::{{detail}}::signature_tag<signature_t<SIGNATURE{{.Index}}>>{}), i._ptr, ::std::forward<Args__>(as)...);\
+    }\
+    template<typename... Args__, ::std::enable_if_t<decltype(interface::{{.Selector}}(::{{detail}}::const_tag{}, ::std::declval<Args__>()...))::value == {{.Index}}, ::{{detail}}::index<{{.Index}}>*> = nullptr>\
+    friend decltype(auto) {{.Name}}(const interface& i, Args__&&... as) noexcept(::std::is_nothrow_invocable_v<typename ::{{detail}}::erasure_fn<signature_t<SIGNATURE{{.Index}}>>::type*, const void*, Args__&&...>)\
+    {\
+        {{- template "debug check call" "i."}}
+        {{- if checked}}
+        if(!i._ptr)\
+            ::{{detail}}::nothing::empty();\
+        {{- end}}
+        return ::{{detail}}::fluent<SIGNATURE{{.Index}}, interface>::call(i, {{.Getter}}(i, ::{{detail}}::interface_tag{}, ::{{detail}}::signature_tag<signature_t<SIGNATURE{{.Index}}>>{}), static_cast<const void*>(i._ptr), ::std::forward<Args__>(as)...);\
+    }\
+    {{- else if refs}}
+    {{- $m := .}}
+    {{- range refs}}
+    template<typename... Args__, ::std::enable_if_t<decltype({{$m.Selector}}({{.Tag}}, ::std::declval<Args__>()...))::value == {{$m.Index}}, ::{{detail}}::index<{{$m.Index}}>*> = nullptr>\
+    decltype(auto) {{$m.Name}}(Args__&&... as) {{.Qualifier}} noexcept(::std::is_nothrow_invocable_v<erasure_fn_t<signature_t<SIGNATURE{{$m.Index}}>>*, {{.Pointer}}, Args__&&...>)\
+    {\
+        {{- template "debug check call" ""}}
+        {{- if checked}}
+        if(!_ptr)\
+            ::{{detail}}::nothing::empty();\
+        {{- end}}
+        return ::{{detail}}::fluent<SIGNATURE{{$m.Index}}, interface>::call(*this, {{$m.Getter}}(*this, ::{{detail}}::interface_tag{}, ::{{detail}}::signature_tag<signature_t<SIGNATURE{{$m.Index}}>>{}), static_cast<{{.Pointer}}>(_ptr), ::std::forward<Args__>(as)...);\
+    }\
+    {{- end}}
+    {{- else}}
+    template<typename... Args__, ::std::enable_if_t<decltype({{.Selector}}(::{{detail}}::mutable_tag{}, ::std::declval<Args__>()...))::value == {{.Index}}, ::{{detail}}::index<{{.Index}}>*> = nullptr>\
+    decltype(auto) {{.Name}}(Args__&&... as) noexcept(::std::is_nothrow_invocable_v<erasure_fn_t<signature_t<SIGNATURE{{.Index}}>>*, void*, Args__&&...>)\
+    {\
+        {{- template "debug check call" ""}}
+        {{- if checked}}
+        if(!_ptr)\
+            ::{{detail}}::nothing::empty();\
+        {{- end}}
+        return ::{{detail}}::fluent<SIGNATURE{{.Index}}, interface>::call(*this, {{.Getter}}(*this, ::{{detail}}::interface_tag{}, ::{{detail}}::signature_tag<signature_t<SIGNATURE{{.Index}}>>{}), _ptr, ::std::forward<Args__>(as)...);\
+    }\
+    template<typename... Args__, ::std::enable_if_t<decltype({{.Selector}}(::{{detail}}::const_tag{}, ::std::declval<Args__>()...))::value == {{.Index}}, ::{{detail}}::index<{{.Index}}>*> = nullptr>\
+    decltype(auto) {{.Name}}(Args__&&... as) const noexcept(::std::is_nothrow_invocable_v<erasure_fn_t<signature_t<SIGNATURE{{.Index}}>>*, const void*, Args__&&...>)\
+    {\
+        {{- template "debug check call" ""}}
+        {{- if checked}}
+        if(!_ptr)\
+            ::{{detail}}::nothing::empty();\
+        {{- end}}
+        return ::{{detail}}::fluent<SIGNATURE{{.Index}}, interface>::call(*this, {{.Getter}}(*this, ::{{detail}}::interface_tag{}, ::{{detail}}::signature_tag<signature_t<SIGNATURE{{.Index}}>>{}), static_cast<const void*>(_ptr), ::std::forward<Args__>(as)...);\
+    }\
+    {{- end}}
+    {{- $m := .}}
+    {{- range .Defaults}}
+\
+    static ::{{detail}}::erasure_fn<signature_t<SIGNATURE{{$m.Index}}>>::omitting<{{$m.Index}}, {{len .}}> {{$m.Selector}};\
+    template<typename... Args__, ::std::enable_if_t<::std::is_same_v<decltype({{$m.Selector}}(::{{detail}}::mutable_tag{}, ::std::declval<Args__>()...)), ::{{detail}}::omitted<{{$m.Index}}, {{len .}}>>, ::{{detail}}::omitted<{{$m.Index}}, {{len .}}>*> = nullptr>\
+    decltype(auto) {{$m.Name}}(Args__&&... as)\
+    {\
+        return {{$m.Name}}(::std::forward<Args__>(as)..., {{template "default args" .}});\
+    }\
+    template<typename... Args__, ::std::enable_if_t<::std::is_same_v<decltype({{$m.Selector}}(::{{detail}}::const_tag{}, ::std::declval<Args__>()...)), ::{{detail}}::omitted<{{$m.Index}}, {{len .}}>>, ::{{detail}}::omitted<{{$m.Index}}, {{len .}}>*> = nullptr>\
+    decltype(auto) {{$m.Name}}(Args__&&... as) const\
+    {\
+        return {{$m.Name}}(::std::forward<Args__>(as)..., {{template "default args" .}});\
+    }\
+    {{- end}}
+    {{- end}}
+{{- end}}
+#define {{.Macro}}{{if .Callable}}_DEFINE{{else}}_{{len .Methods}}{{end}}(NAME, {{template "macro args" .Methods}})\
+class {{exported}}NAME : ::{{detail}}::interface_tag\
+{\
+    {{- template "no methods" .}}
+    {{- template "reserved names" .}}
+    using interface = NAME;\
+    template<typename S__>\
+    using signature_t = typename ::{{detail}}::fluent<S__, interface>::type;\
+\
+    {{- template "method decls" .}}
+\
+    friend auto fetch_ptr(const interface& i, ::{{detail}}::interface_tag)\
+    {\
+        return i._ptr;\
+    }\
+\
+    friend auto&& fetch_thunk(const interface& i, ::{{detail}}::interface_tag)\
+    {\
+        return i._t;\
+    }\
+    {{- if pmr}}
+\
+    friend auto fetch_resource(const interface& i, ::{{detail}}::interface_tag)\
+    {\
+        return i._mr;\
+    }\
+    {{- end}}
+    {{- if tag}}
+\
+    friend ::std::uint32_t fetch_tag(const interface& i, ::{{detail}}::interface_tag)\
+    {\
+        return i._tag;\
+    }\
+    friend void store_tag(interface& i, ::{{detail}}::interface_tag, ::std::uint32_t tag) noexcept\
+    {\
+        i._tag = tag;\
+    }\
+    {{- end}}
+\
+    friend const void* fetch_vtable(const interface& i, ::{{detail}}::interface_tag)\
+    {\
+        return i._vtable;\
+    }\
+\
+    friend void release(interface& i, ::{{detail}}::interface_tag) noexcept\
+    {\
+        i._ptr = nullptr;\
+        i._t = nullptr;\
+        i._vtable = nullptr;\
+    }\
+\
+    template<typename U__>\
+    friend const void* vtable_for(const interface&, ::{{detail}}::interface_tag, ::std::in_place_type_t<U__>)\
+    {\
+        return make_vtable<U__>();\
+    }\
+    template<typename I__, ::std::enable_if_t<::{{detail}}::is_interface_v<I__>, bool> = false>\
+    friend const void* vtable_for(const interface&, ::{{detail}}::interface_tag, const I__& i)\
+    {\
+        return convert_vtable(i);\
+    }\
+    friend void alias(interface& i, ::{{detail}}::interface_tag, const void* vtable, void* p, const ::{{detail}}::thunk* t) noexcept\
+    {\
+        i._ptr = p;\
+        i._t = t;\
+        i._vtable = static_cast<const vtable_t*>(vtable);\
+    }\
+    {{- if .Shared}}
+\
+    friend void adopt(interface& i, ::{{detail}}::interface_tag, const void* vtable, const ::{{detail}}::thunk* t, ::{{detail}}::shared_block* b{{if pmr}}, ::std::pmr::memory_resource* mr{{end}}{{if tag}}, ::std::uint32_t tag{{end}})\
+    {\
+        {{- if pmr}}
+        i._mr = mr;\
+        {{- end}}
+        {{- if tag}}
+        i._tag = tag;\
+        {{- end}}
+        auto buf = ::{{detail}}::buffer{t->inline_storage ? nullptr : ::{{detail}}::allocate(t{{if pmr}}, mr{{end}}), {t{{if pmr}}, mr{{end}}}};\
+        if(!b->acquire())\
+            return;\
+        auto dst = buf ? buf.get() : i._buf.get();\
+        t->adopt(dst, b);\
+        i._ptr = ::std::launder(dst);\
+        buf.release();\
+        i._t = t;\
+        i._vtable = static_cast<const vtable_t*>(vtable);\
+    }\
+    {{- end}}
+\
+    {{- template "vtables" .}}
+\
+    template<typename I__>\
+    void construct(I__&& i)\
+    {\
+        {{- if debugChecks}}
+        ::{{detail}}::check(fetch_ptr(i, ::{{detail}}::interface_tag{}), fetch_thunk(i, ::{{detail}}::interface_tag{}), fetch_vtable(i, ::{{detail}}::interface_tag{}));\
+        {{- end}}
+        {{- if pmr}}
+        _mr = fetch_resource(i, ::{{detail}}::interface_tag{});\
+        {{- end}}
+        {{- if tag}}
+        _tag = fetch_tag(i, ::{{detail}}::interface_tag{});\
+        {{- end}}
+        if(!i)\
+            return;\
+\
+        auto p = fetch_ptr(i, ::{{detail}}::interface_tag{});\
+        auto t = fetch_thunk(i, ::{{detail}}::interface_tag{});\
+        constexpr bool copying = ::std::is_lvalue_reference_v<I__> || ::std::is_const_v<I__>;\
+        const bool steal = !copying && !t->inline_storage;\
+        if(steal)\
+            _ptr = p;\
+        else\
+        {\
+            auto buf = ::{{detail}}::buffer{t->inline_storage ? nullptr : ::{{detail}}::allocate(t{{if pmr}}, _mr{{end}}), {t{{if pmr}}, _mr{{end}}}};\
+            auto dst = buf ? buf.get() : _buf.get();\
+            if constexpr(copying)\
+            {\
+                static_assert(::std::is_copy_constructible_v<::std::decay_t<I__>>, "Copying requires a copyable interface.");\
+                t->copy(dst, p);\
+            }\
+            else\
+                t->move(dst, p);\
+            _ptr = ::std::launder(dst);\
+            buf.release();\
+        }\
+        _t = t;\
+        _vtable = convert_vtable(i);\
+        if constexpr(!copying)\
+            if(steal)\
+                release(i, ::{{detail}}::interface_tag{});\
+    }\
+\
+    template<typename U__, typename... Args__>\
+    void emplace__(Args__&&... as)\
+    {\
+        if constexpr(::{{detail}}::is_inline_v<U__>)\
+            _ptr = new (_buf.get()) U__{::std::forward<Args__>(as)...};\
+        else\
+        {\
+            auto t = ::{{detail}}::get_thunk<U__>();\
+            auto buf = ::{{detail}}::buffer{::{{detail}}::allocate(t{{if pmr}}, _mr{{end}}), {t{{if pmr}}, _mr{{end}}}};\
+            _ptr = new (buf.get()) U__{::std::forward<Args__>(as)...};\
+            buf.release();\
+        }\
+        _t = ::{{detail}}::get_thunk<U__>();\
+        _vtable = make_vtable<U__>();\
+    }\
+\
+    void take(interface& other) noexcept\
+    {\
+        {{- if pmr}}
+        _mr = other._mr;\
+        {{- end}}
+        {{- if tag}}
+        _tag = other._tag;\
+        {{- end}}
+        if(!other._ptr)\
+            return;\
+\
+        if(other._t->inline_storage)\
+        {\
+            other._t->relocate(_buf.get(), other._ptr);\
+            _ptr = ::std::launder(_buf.get());\
+        }\
+        else\
+            _ptr = other._ptr;\
+\
+        _t = other._t;\
+        _vtable = other._vtable;\
+        other._ptr = nullptr;\
+        other._t = nullptr;\
+        other._vtable = nullptr;\
+    }\
+\
+public:\
+    static constexpr ::std::size_t method_count = {{len .Methods}};\
+    template<::std::size_t I__>\
+    using method_signature = typename ::{{detail}}::nth_signature<I__{{range .Methods}}, SIGNATURE{{.Index}}{{end}}>::type;\
+    {{- template "reflect" .}}
+    static constexpr ::{{detail}}::none_t none{0};\
+    {{- if relocatable}}
+    using IsRelocatable = ::std::true_type;\
+    {{- end}}
+\
+    {{- if concepts}}
+    template<typename T__>\
+    static constexpr bool implemented_by = {{template "implemented by" .Methods}};\
+\
+    {{- end}}
+    {{- if doc}}
+    /** @brief Constructs an empty NAME. */\
+    {{- end}}
+    NAME() = default;\
+    NAME(::{{detail}}::none_t) noexcept {}\
+    NAME(interface&& other) noexcept { take(other); }\
+    {{- if .Copyable}}
+    NAME(const interface& other) { construct(other); }\
+    {{- else}}
+    NAME(const interface& other) = delete;\
+    {{- end}}
+    {{- if doc}}
+    /** @brief Converts an interface providing every method of NAME, taking or copying its object. */\
+    {{- end}}
+    template<typename I__, ::std::enable_if_t<::{{detail}}::is_interface_v<::std::decay_t<I__>>, bool> = false>\
+    NAME(I__&& i)\
+    {\
+        {{- if .Copyable}}
+        static_assert(::std::is_copy_constructible_v<::std::decay_t<I__>>, "Value semantics require the interface be copy constructible.");\
+        {{- end}}
+        construct(::std::forward<I__>(i));\
+    }\
+\
+\
+    {{- if doc}}
+    /** @brief Constructs NAME holding t{{if .Methods}}, which must provide {{range $k, $v := .Methods}}{{if $k}}, {{end}}{{$v.Name}}{{end}}{{end}}. */\
+    {{- end}}
+    template<typename T__, ::std::enable_if_t<!::{{detail}}::is_interface_v<::std::decay_t<T__>> && !::{{detail}}::is_in_place_type<::std::decay_t<T__>>::value, bool> = false>\
+    {{- if concepts}}
+        requires implemented_by<::{{detail}}::stored_t<T__>>\
+    {{- end}}
+    NAME(T__&& t) : NAME(::std::in_place_type<::{{detail}}::stored_t<T__>>, ::{{detail}}::unwrap(::std::forward<T__>(t)))\
+    {\
+        {{- range .Methods}}
+        static_assert(::{{detail}}::erasure_fn<signature_t<SIGNATURE{{.Index}}>>::template implemented_by<{{.Factory}}<::{{detail}}::stored_t<T__>>>,\
+            {{if $.Callable}}"type is not callable with the required signature"{{else if .Free}}"no function " #{{.Name}} " takes the type with the required signature"{{else}}"type does not provide " #{{.Name}} " with the required signature"{{end}});\
+        {{- end}}
+    }\
+\
+    {{- if .Shared}}
+    template<typename U__, typename... Args__, ::std::enable_if_t<!::std::is_same_v<U__, ::{{detail}}::shared_t<U__>>, bool> = false>\
+    explicit NAME(::std::in_place_type_t<U__>, Args__&&... as)\
+        : NAME(::std::in_place_type<::{{detail}}::shared_t<U__>>, ::std::in_place, ::std::forward<Args__>(as)...)\
+    {\
+    }\
+\
+    template<typename U__, typename... Args__, ::std::enable_if_t<::std::is_same_v<U__, ::{{detail}}::shared_t<U__>>, bool> = false>\
+    {{- else}}
+    template<typename U__, typename... Args__>\
+    {{- end}}
+    explicit NAME(::std::in_place_type_t<U__>, Args__&&... as)\
+    {\
+        emplace__<U__>(::std::forward<Args__>(as)...);\
+    }\
+    {{- if pmr}}
+\
+    template<typename T__, ::std::enable_if_t<!::{{detail}}::is_interface_v<::std::decay_t<T__>> && !::{{detail}}::is_in_place_type<::std::decay_t<T__>>::value, bool> = false>\
+    NAME(::std::allocator_arg_t, ::std::pmr::memory_resource* mr, T__&& t)\
+        : NAME(::std::allocator_arg, mr, ::std::in_place_type<::{{detail}}::stored_t<T__>>, ::{{detail}}::unwrap(::std::forward<T__>(t)))\
+    {\
+    }\
+    template<typename U__, typename... Args__>\
+    NAME(::std::allocator_arg_t, ::std::pmr::memory_resource* mr, ::std::in_place_type_t<U__>, Args__&&... as) : _mr{mr}\
+    {\
+        emplace<U__>(::std::forward<Args__>(as)...);\
+    }\
+    {{- end}}
+    {{- if tag}}
+\
+    {{- if doc}}
+    /** @brief Constructs NAME holding t, with the tag returned by tag(). */\
+    {{- end}}
+    template<typename T__, ::std::enable_if_t<!::{{detail}}::is_interface_v<::std::decay_t<T__>> && !::{{detail}}::is_in_place_type<::std::decay_t<T__>>::value, bool> = false>\
+    NAME(T__&& t, ::std::uint32_t tag) : NAME(::std::forward<T__>(t))\
+    {\
+        _tag = tag;\
+    }\
+    {{- end}}
+\
+    {{- if debugChecks}}
+    ~NAME()\
+    {\
+        reset();\
+        ::{{detail}}::poison(_ptr, _t, _vtable);\
+    }\
+    {{- else}}
+    ~NAME() { reset(); }\
+    {{- end}}
+\
+    {{- if .Copyable}}
+    interface& operator=(const interface& other)\
+    {\
+        {{- template "debug check" ""}}
+        {{- template "debug check" "other."}}
+        if(this == &other)\
+            return *this;\
+        auto tmp = other;\
+        swap(*this, tmp);\
+        return *this;\
+    }\
+    {{- else}}
+    interface& operator=(const interface& other) = delete;\
+    {{- end}}
+    interface& operator=(interface&& other) noexcept\
+    {\
+        if(this == &other)\
+            return *this;\
+        auto tmp = ::std::move(other);\
+        reset();\
+        take(tmp);\
+        return *this;\
+    }\
+    interface& operator=(::{{detail}}::none_t) noexcept\
+    {\
+        reset();\
+        return *this;\
+    }\
+\
+    {{- template "method defs" .}}
+\
+    {{- if doc}}
+    /** @brief Returns the held object if it is a T__, or the object referred to by a held T__*, otherwise nullptr. */\
+    {{- end}}
+    {{- if memberTarget}}
+    template<typename T__>\
+    ::{{detail}}::target_t<T__>* target() noexcept\
+    {\
+        return static_cast<::{{detail}}::target_t<T__>*>(::{{detail}}::get_object<T__>(_t, _ptr));\
+    }\
+    template<typename T__>\
+    const ::{{detail}}::target_t<T__>* target() const noexcept\
+    {\
+        return static_cast<const ::{{detail}}::target_t<T__>*>(::{{detail}}::get_object<T__>(_t, _ptr));\
+    }\
+    {{- else}}
+    template<typename T__>\
+    friend ::{{detail}}::target_t<T__>* target(interface&& i) noexcept\
+    {\
+        return static_cast<::{{detail}}::target_t<T__>*>(::{{detail}}::get_object<T__>(i._t, i._ptr));\
+    }\
+    template<typename T__>\
+    friend ::{{detail}}::target_t<T__>* target(interface& i) noexcept\
+    {\
+        return static_cast<::{{detail}}::target_t<T__>*>(::{{detail}}::get_object<T__>(i._t, i._ptr));\
+    }\
+    template<typename T__>\
+    friend const ::{{detail}}::target_t<T__>* target(const interface& i) noexcept\
+    {\
+        return static_cast<const ::{{detail}}::target_t<T__>*>(::{{detail}}::get_object<T__>(i._t, i._ptr));\
+    }\
+    {{- end}}
+\
+    template<typename T__>\
+    ::{{detail}}::target_t<T__>* get_if() noexcept\
+    {\
+        return static_cast<::{{detail}}::target_t<T__>*>(::{{detail}}::get_object<T__>(_t, _ptr));\
+    }\
+    template<typename T__>\
+    const ::{{detail}}::target_t<T__>* get_if() const noexcept\
+    {\
+        return static_cast<const ::{{detail}}::target_t<T__>*>(::{{detail}}::get_object<T__>(_t, _ptr));\
+    }\
+    template<typename T__>\
+    bool holds() const noexcept\
+    {\
+        return get_if<T__>();\
+    }\
+    const void* underlying_address() const noexcept\
+    {\
+        return ::{{detail}}::underlying_address(_t, _ptr);\
+    }\
+    bool has_reference_semantics() const noexcept\
+    {\
+        return ::{{detail}}::is_pointer_thunk(_t);\
+    }\
+    const void* type_id() const noexcept { return _t; }\
+    {{- if pmr}}
+    ::std::pmr::memory_resource* resource() const noexcept\
+    {\
+        return _mr ? _mr : ::std::pmr::new_delete_resource();\
+    }\
+    {{- end}}
+    {{- if tag}}
+    {{- if doc}}
+    /** @brief Returns the tag given on construction, 0 if none was. */\
+    {{- end}}
+    ::std::uint32_t tag() const noexcept { return _tag; }\
+    {{- end}}
+\
+    {{- if doc}}
+    /** @brief Whether NAME holds an object. */\
+    {{- end}}
+    explicit operator bool() const noexcept { return _ptr; }\
+    {{- if and stream (not .Callable)}}
+\
+    template<typename I__ = interface, typename = decltype({{if .Free}}{{stream}}(::std::declval<const I__&>(), ::std::declval<::std::ostream&>()){{else}}::std::declval<const I__&>().{{stream}}(::std::declval<::std::ostream&>()){{end}})>\
+    friend ::std::ostream& operator<<(::std::ostream& os, const interface& i)\
+    {\
+        {{if .Free}}{{stream}}(static_cast<const I__&>(i), os){{else}}static_cast<const I__&>(i).{{stream}}(os){{end}};\
+        return os;\
+    }\
+    {{- end}}
+    {{- if and (eq (len .Methods) 1) .Copyable (not .Callable)}}
+    {{- with index .Methods 0}}
+\
+    {{- if refs}}
+    template<typename S__ = SIGNATURE0, ::std::enable_if_t<!::{{detail}}::is_rvalue_qualified_v<S__>>* = nullptr>\
+    {{- end}}
+    operator typename ::{{detail}}::erasure_fn<typename ::{{detail}}::fluent<SIGNATURE0, interface>::declared>::function() const\
+    {\
+        return [i = *this](auto&&... as) mutable -> decltype(auto) {\
+            return {{if .Free}}{{.Name}}(i, {{else}}i.{{.Name}}({{end}}::std::forward<decltype(as)>(as)...);\
+        };\
+    }\
+    {{- end}}
+    {{- end}}
+    {{- if and .Copyable (not .Shared)}}
+\
+    template<bool B__ = !::{{detail}}::is_named("clone", { {{- template "named methods" .}}}), ::std::enable_if_t<B__, bool> = false>\
+    interface clone() const\
+    {\
+        if(!::{{detail}}::is_pointer_thunk(_t))\
+            return *this;\
+\
+        interface i;\
+        auto t = _t->clone;\
+        if(!t)\
+            return i;\
+        {{- if pmr}}
+        i._mr = _mr;\
+        {{- end}}
+        {{- if tag}}
+        i._tag = _tag;\
+        {{- end}}
+        auto buf = ::{{detail}}::buffer{t->inline_storage ? nullptr : ::{{detail}}::allocate(t{{if pmr}}, i._mr{{end}}), {t{{if pmr}}, i._mr{{end}}}};\
+        auto dst = buf ? buf.get() : i._buf.get();\
+        t->copy(dst, _ptr);\
+        i._ptr = ::std::launder(dst);\
+        buf.release();\
+        i._t = t;\
+        i._vtable = _vtable;\
+        return i;\
+    }\
+    {{- end}}
+    {{- if rtti}}
+\
+    const ::std::type_info& target_type() const noexcept\
+    {\
+        return _t ? *_t->type : typeid(void);\
+    }\
+    {{- end}}
+\
+    void reset() noexcept\
+    {\
+        if(!_ptr)\
+            return;\
+        _t->destroy(_ptr);\
+        if(!_t->inline_storage)\
+            ::{{detail}}::deallocate(_ptr, _t{{if pmr}}, _mr{{end}});\
+        _ptr = nullptr;\
+        _t = nullptr;\
+        _vtable = nullptr;\
+    }\
+\
+    template<typename T__, typename... Args__>\
+    T__& emplace(Args__&&... as)\
+    {\
+        reset();\
+        {{- if .Shared}}
+        if constexpr(::std::is_same_v<T__, ::{{detail}}::shared_t<T__>>)\
+            emplace__<T__>(::std::forward<Args__>(as)...);\
+        else\
+            emplace__<::{{detail}}::shared_t<T__>>(::std::in_place, ::std::forward<Args__>(as)...);\
+        {{- else}}
+        emplace__<T__>(::std::forward<Args__>(as)...);\
+        {{- end}}
+        return *get_if<T__>();\
+    }\
+\
+    {{- if comparable}}
+    template<typename I__, std::enable_if_t<std::is_same_v<interface, std::decay_t<I__>>, bool> = false>\
+    ::std::weak_ordering operator<=>(I__&& rhs) const\
+    {\
+        if(_t != rhs._t)\
+            return ::std::compare_three_way{}(_t, rhs._t);\
+        if(!_ptr)\
+            return ::std::weak_ordering::equivalent;\
+        if(::{{detail}}::is_pointer_thunk(_t))\
+            return ::std::compare_three_way{}(*reinterpret_cast<void**>(_ptr), *reinterpret_cast<void**>(rhs._ptr));\
+        if(_t->compare)\
+            return _t->compare(_ptr, rhs._ptr) <=> 0;\
+        return ::std::compare_three_way{}(_ptr, rhs._ptr);\
+    }\
+    template<typename I__, std::enable_if_t<std::is_same_v<interface, std::decay_t<I__>>, bool> = false>\
+    bool operator==(I__&& rhs) const { return (*this <=> rhs) == 0; }\
+    template<typename I__, std::enable_if_t<std::is_same_v<interface, std::decay_t<I__>>, bool> = false>\
+    bool operator!=(I__&& rhs) const { return !(*this == rhs); }\
+    {{- else}}
+    template<typename I__, std::enable_if_t<std::is_same_v<interface, std::decay_t<I__>>, bool> = false>\
+    bool operator==(I__&& rhs) const{{if not equality}} noexcept{{end}}\
+    {\
+        if(!_ptr)\
+            return !rhs._ptr;\
+        if(::{{detail}}::is_pointer_thunk(_t) && ::{{detail}}::is_pointer_thunk(rhs._t))\
+            return *reinterpret_cast<void**>(_ptr) == *reinterpret_cast<void**>(rhs._ptr);\
+        {{- if equality}}
+        if(_t == rhs._t && _t->equals)\
+            return _t->equals(_ptr, rhs._ptr);\
+        {{- end}}
+        return false;\
+    }\
+    template<typename I__, std::enable_if_t<std::is_same_v<interface, std::decay_t<I__>>, bool> = false>\
+    bool operator!=(I__&& rhs) const{{if not equality}} noexcept{{end}} { return !(*this == rhs); }\
+    {{- end}}
+\
+    friend bool operator==(const interface& i, ::{{detail}}::none_t) noexcept { return !i; }\
+    friend bool operator==(::{{detail}}::none_t, const interface& i) noexcept { return !i; }\
+    friend bool operator!=(const interface& i, ::{{detail}}::none_t) noexcept { return bool(i); }\
+    friend bool operator!=(::{{detail}}::none_t, const interface& i) noexcept { return bool(i); }\
+    template<typename T__, ::std::enable_if_t<::{{detail}}::is_held_comparable_v<T__>, bool> = false>\
+    friend bool operator==(const interface& i, const T__& v) { return ::{{detail}}::holds_equal(i, v); }\
+    template<typename T__, ::std::enable_if_t<::{{detail}}::is_held_comparable_v<T__>, bool> = false>\
+    friend bool operator==(const T__& v, const interface& i) { return ::{{detail}}::holds_equal(i, v); }\
+    template<typename T__, ::std::enable_if_t<::{{detail}}::is_held_comparable_v<T__>, bool> = false>\
+    friend bool operator!=(const interface& i, const T__& v) { return !::{{detail}}::holds_equal(i, v); }\
+    template<typename T__, ::std::enable_if_t<::{{detail}}::is_held_comparable_v<T__>, bool> = false>\
+    friend bool operator!=(const T__& v, const interface& i) { return !::{{detail}}::holds_equal(i, v); }\
+\
+    {{- if doc}}
+    /** @brief Swaps the objects held by x and y, never throws. */\
+    {{- end}}
+    friend void swap(interface& x, interface& y) noexcept\
+    {\
+        interface tmp = ::std::move(x);\
+        x.take(y);\
+        y.take(tmp);\
+    }\
+\
+private:\
+    template<typename T__>\
+    using erasure_fn_t = typename ::{{detail}}::erasure_fn<T__>::type;\
+    using vtable_t = ::std::array<::{{detail}}::slot, {{len .Methods}}>;\
+    {{- if debugChecks}}
+\
+    void debug_check__() const noexcept { ::{{detail}}::{{if checked}}check{{else}}check_held{{end}}(_ptr, _t, _vtable); }\
+    {{- end}}
+    {{- if abi}}
+\
+    static void check_abi__() noexcept\
+    {\
+        static_assert(sizeof(interface) == ::{{detail}}::abi_size({{template "abi layout"}}), "The layout of the interface changed.");\
+        static_assert(alignof(interface) == ::{{detail}}::abi_align({{template "abi align"}}), "The alignment of the interface changed.");\
+    }\
+    {{- end}}
+\
+    void* _ptr = nullptr;\
+    const ::{{detail}}::thunk* _t = nullptr;\
+    const vtable_t* _vtable = nullptr;\
+    ::{{detail}}::inline_buffer<> _buf;\
+    {{- if pmr}}
+    ::std::pmr::memory_resource* _mr = nullptr;\
+    {{- end}}
+    {{- if tag}}
+    ::std::uint32_t _tag = 0;\
+    {{- end}}
+}
+`
+
+// view_str defines the macros of non-owning views, sharing the methods of interface_str.
+// Views hold a pointer to the object and the vtable, which are trivially copied.
+var view_str = `#define {{.Macro}}_{{len .Methods}}(NAME, {{template "macro args" .Methods}})\
+class {{exported}}NAME : ::{{detail}}::view_tag\
+{\
+    {{- template "no methods" .}}
+    {{- template "reserved names" .}}
+    using interface = NAME;\
+    template<typename S__>\
+    using signature_t = typename ::{{detail}}::fluent<S__, interface>::type;\
+\
+    {{- template "method decls" .}}
+\
+    friend void* fetch_ptr(const interface& i, ::{{detail}}::interface_tag) noexcept\
+    {\
+        return i._ptr;\
+    }\
+\
+    friend const void* fetch_vtable(const interface& i, ::{{detail}}::interface_tag) noexcept\
+    {\
+        return i._vtable;\
+    }\
+\
+    {{- template "vtables" .}}
+\
+public:\
+    static constexpr ::std::size_t method_count = {{len .Methods}};\
+    template<::std::size_t I__>\
+    using method_signature = typename ::{{detail}}::nth_signature<I__{{range .Methods}}, SIGNATURE{{.Index}}{{end}}>::type;\
+    {{- template "reflect" .}}
+    static constexpr ::{{detail}}::none_t none{0};\
+\
+    {{- if doc}}
+    /** @brief Constructs an empty NAME, referring to no object. */\
+    {{- end}}
+    NAME() = default;\
+    NAME(::{{detail}}::none_t) noexcept {}\
+\
+    {{- if doc}}
+    /** @brief Constructs NAME referring to t, which must {{if .Methods}}provide {{range $k, $v := .Methods}}{{if $k}}, {{end}}{{$v.Name}}{{end}} and {{end}}outlive NAME. */\
+    {{- end}}
+    template<typename T__, ::std::enable_if_t<::std::is_lvalue_reference_v<T__> && !::{{detail}}::is_interface_v<::std::decay_t<T__>> && !::{{detail}}::is_view_v<::std::decay_t<T__>>, bool> = false>\
+    NAME(T__&& t) noexcept\
+        : _ptr{const_cast<void*>(static_cast<const volatile void*>(::std::addressof(t)))},\
+          _vtable{make_vtable<::std::remove_reference_t<T__>>()}\
+    {\
+        {{- range .Methods}}
+        static_assert(::{{detail}}::erasure_fn<signature_t<SIGNATURE{{.Index}}>>::template implemented_by<{{.Factory}}<::std::remove_reference_t<T__>>>,\
+            "type does not provide " #{{.Name}} " with the required signature");\
+        {{- end}}
+    }\
+    template<typename T__, ::std::enable_if_t<!::std::is_lvalue_reference_v<T__> && !::{{detail}}::is_view_v<::std::decay_t<T__>>, bool> = false>\
+    NAME(T__&& t) = delete;\
+\
+    {{- if doc}}
+    /** @brief Refers to the object of an interface or view providing every method of NAME. */\
+    {{- end}}
+    template<typename I__, ::std::enable_if_t<(::{{detail}}::is_interface_v<::std::decay_t<I__>> && ::std::is_lvalue_reference_v<I__>)\
+        || (::{{detail}}::is_view_v<::std::decay_t<I__>> && !::std::is_same_v<::std::decay_t<I__>, interface>), bool> = false>\
+    NAME(I__&& i) : _ptr{i ? fetch_ptr(i, ::{{detail}}::interface_tag{}) : nullptr}, _vtable{i ? convert_vtable(i) : nullptr}\
+    {\
+    }\
+\
+    {{- template "method defs" .}}
+\
+    {{- if doc}}
+    /** @brief Whether NAME refers to an object. */\
+    {{- end}}
+    explicit operator bool() const noexcept { return _ptr; }\
+    const void* underlying_address() const noexcept { return _ptr; }\
+    bool has_reference_semantics() const noexcept { return _ptr; }\
+\
+    friend bool operator==(const interface& i, ::{{detail}}::none_t) noexcept { return !i; }\
+    friend bool operator==(::{{detail}}::none_t, const interface& i) noexcept { return !i; }\
+    friend bool operator!=(const interface& i, ::{{detail}}::none_t) noexcept { return bool(i); }\
+    friend bool operator!=(::{{detail}}::none_t, const interface& i) noexcept { return bool(i); }\
+\
+private:\
+    template<typename T__>\
+    using erasure_fn_t = typename ::{{detail}}::erasure_fn<T__>::type;\
+    using vtable_t = ::std::array<::{{detail}}::slot, {{len .Methods}}>;\
+    {{- if debugChecks}}
+\
+    void debug_check__() const noexcept { ::{{detail}}::{{if checked}}check{{else}}check_held{{end}}(_ptr, _vtable); }\
+    {{- end}}
+    {{- if abi}}
+\
+    static void check_abi__() noexcept\
+    {\
+        static_assert(sizeof(interface) == 2 * sizeof(void*), "The layout of the view changed.");\
+        static_assert(alignof(interface) == alignof(void*), "The alignment of the view changed.");\
+    }\
+    {{- end}}
+\
+    void* _ptr = nullptr;\
+    const vtable_t* _vtable = nullptr;\
+}
+`
+
+var footer = `{{define "dash"}}
+    {{- range $k, $v := . -}}
+        {{if $k}}, {{end -}}
+        _{{.}}a, _{{.}}{{"b" -}}
+    {{end}}
+{{- end}}
+{{define "triple dash"}}
     {{- range $k, $v := . -}}
         {{if $k}}, {{end -}}
-        SIGNATURE{{$v}}, METHOD_NAME{{$v -}}
+        _{{.}}a, _{{.}}b, _{{.}}{{"c" -}}
+    {{end}}
+{{- end}}
+{{define "name dash"}}
+    {{- $macro := .Macro}}
+    {{- range $k, $v := .Arities -}}
+        {{if $k}}, {{end -}}
+        {{$macro}}_{{.}}, {{if $.Default}}_{{.}}, {{end}}{{if eq . 1}}{{$macro}}_0{{else}}_{{.}}{{end -}}
     {{end}}
 {{- end}}
-{{- define "vtable funcs"}}
-    {{- range $k, $v := . -}}
-        {{if $k}}, {{end -}}
-        erasure_fn_t<SIGNATURE{{$v -}}>*
-    {{- end}}
+{{- define "anonymous"}}INTERFACE_APPEND_{{if counter}}COUNTER{{else}}LINE{{end}}(interface__){{end}}
+{{- if not module}}
+// Overloaded macros through __VA_ARGS__ hacking.
+// Selects implementation by argument count.
+#define GET_INTERFACE_FROM({{template "dash" (index . 0).Arities}}, x, ...) x
+// INTERFACE_DEFAULT takes three arguments per method.
+#define GET_INTERFACE_DEFAULT_FROM({{template "triple dash" (index . 0).Arities}}, x, ...) x
+{{- range .}}
+{{- if .Callable}}
+#define {{.Macro}}(SIGNATURE0) {{.Macro}}_DEFINE({{template "anonymous"}}, SIGNATURE0)
+{{- else}}
+#define {{.Macro}}_DEFINE(NAME, ...)\
+GET_INTERFACE_{{if .Default}}DEFAULT_{{end}}FROM(__VA_ARGS__, {{template "name dash" .}})(NAME, __VA_ARGS__)
+#define {{.Macro}}(...) {{.Macro}}_DEFINE({{template "anonymous"}}, __VA_ARGS__)
+{{- end}}
+#define {{.Macro}}_DEFINE_IN(NS, NAME, ...)\
+namespace NS { {{.Macro}}_DEFINE(NAME, __VA_ARGS__); } static_assert(::{{detail}}::{{if .View}}is_view_v{{else}}is_interface_v{{end}}<NS::NAME>)
+{{end}}
+// X_DEFINE_IN defines the named interface within the namespace NS, which may be nested.
+// The trailing static_assert takes the semicolon following the macro.
+// Named interfaces are forward declared as classes, the methods are only needed by the definition.
+#define INTERFACE_DECLARE(NAME, ...) class {{exported}}NAME
+{{- end}}
+
+// Fixed capacity array of up to N interfaces I, holding objects small enough to be stored within I,
+// so that no element allocates. Elements are destroyed through the thunks of their objects.
+// Adding to a full array throws std::length_error.
+{{export}}template<typename I, ::std::size_t N>
+class interface_array
+{
+    static_assert(::{{detail}}::is_interface_v<I>, "I must be an interface.");
+    static_assert(N > 0, "interface_array must have a capacity.");
+
+public:
+    using value_type = I;
+    using size_type = ::std::size_t;
+    using iterator = I*;
+    using const_iterator = const I*;
+
+    interface_array() noexcept = default;
+    interface_array(const interface_array& other)
+    {
+        for(auto& i : other)
+            push_back(i);
+    }
+    interface_array(interface_array&& other) noexcept
+    {
+        for(auto& i : other)
+            push_back(::std::move(i));
+        other.clear();
+    }
+    interface_array& operator=(const interface_array& other)
+    {
+        if(this != &other)
+        {
+            clear();
+            for(auto& i : other)
+                push_back(i);
+        }
+        return *this;
+    }
+    interface_array& operator=(interface_array&& other) noexcept
+    {
+        if(this != &other)
+        {
+            clear();
+            for(auto& i : other)
+                push_back(::std::move(i));
+            other.clear();
+        }
+        return *this;
+    }
+    ~interface_array() { clear(); }
+
+    // Converts t to I at the end of the array, t must be stored within I unless it is an interface.
+    template<typename T>
+    I& push_back(T&& t)
+    {
+        if constexpr(!::{{detail}}::is_interface_v<::std::decay_t<T>>)
+            static_assert(::{{detail}}::is_inline_v<::{{detail}}::stored_t<T>>,
+                "Elements of interface_array must be small enough to be stored within the interface.");
+        auto& i = *new(slot()) I(::std::forward<T>(t));
+        ++_size;
+        return i;
+    }
+
+    // Constructs T from args within I at the end of the array.
+    template<typename T, typename... Args>
+    I& emplace_back(Args&&... args)
+    {
+        static_assert(::{{detail}}::is_inline_v<T>,
+            "Elements of interface_array must be small enough to be stored within the interface.");
+        auto& i = *new(slot()) I(::std::in_place_type<T>, ::std::forward<Args>(args)...);
+        ++_size;
+        return i;
+    }
+
+    void pop_back() noexcept { data()[--_size].~I(); }
+
+    void clear() noexcept
+    {
+        while(_size)
+            pop_back();
+    }
+
+    I& operator[](size_type n) noexcept { return data()[n]; }
+    const I& operator[](size_type n) const noexcept { return data()[n]; }
+
+    I* data() noexcept { return ::std::launder(reinterpret_cast<I*>(_storage)); }
+    const I* data() const noexcept { return ::std::launder(reinterpret_cast<const I*>(_storage)); }
+
+    iterator begin() noexcept { return data(); }
+    iterator end() noexcept { return data() + _size; }
+    const_iterator begin() const noexcept { return data(); }
+    const_iterator end() const noexcept { return data() + _size; }
+
+    size_type size() const noexcept { return _size; }
+    bool empty() const noexcept { return _size == 0; }
+    bool full() const noexcept { return _size == N; }
+    static constexpr size_type capacity() noexcept { return N; }
+
+private:
+    // Storage for the next element, the size is only incremented once it is constructed.
+    void* slot()
+    {
+        if(full())
+            throw ::std::length_error{"interface_array is full"};
+        return _storage + sizeof(I) * _size;
+    }
+
+    alignas(I) ::std::byte _storage[sizeof(I) * N];
+    size_type _size = 0;
+};
+
+// Non-owning reference to the object shared by an INTERFACE_SHARED interface I, like std::weak_ptr.
+// The object may be destroyed while referred to, lock returns an I sharing it if it is still alive.
+// Objects not owned by I, such as those referred to through pointers, are never observed and
+// weak references to them are always expired.
+{{export}}template<typename I>
+class weak_interface
+{
+    static_assert(::{{detail}}::is_shared_interface<I>::value, "I must be an INTERFACE_SHARED interface.");
+
+public:
+{{- if relocatable}}
+    using IsRelocatable = ::std::true_type;
+
+{{- end}}
+    weak_interface() noexcept = default;
+    weak_interface(const I& i) noexcept
+    {
+        auto t = fetch_thunk(i, ::{{detail}}::interface_tag{});
+        if(!t || !t->block)
+            return;
+        _b = t->block(fetch_ptr(i, ::{{detail}}::interface_tag{}));
+        _b->weak.fetch_add(1, ::std::memory_order_relaxed);
+        _t = t;
+        _vtable = fetch_vtable(i, ::{{detail}}::interface_tag{});
+{{- if pmr}}
+        _mr = fetch_resource(i, ::{{detail}}::interface_tag{});
+{{- end}}
+{{- if tag}}
+        _tag = fetch_tag(i, ::{{detail}}::interface_tag{});
+{{- end}}
+    }
+    weak_interface(const weak_interface& other) noexcept
+        : _b{other._b}, _t{other._t}, _vtable{other._vtable}{{if pmr}}, _mr{other._mr}{{end}}{{if tag}}, _tag{other._tag}{{end}}
+    {
+        if(_b)
+            _b->weak.fetch_add(1, ::std::memory_order_relaxed);
+    }
+    weak_interface(weak_interface&& other) noexcept { swap(*this, other); }
+    weak_interface& operator=(weak_interface other) noexcept
+    {
+        swap(*this, other);
+        return *this;
+    }
+    ~weak_interface()
+    {
+        if(_b)
+            _b->release_weak();
+    }
+
+    // Returns an I sharing the object, or an empty I if it has been destroyed.
+    I lock() const
+    {
+        I i;
+        if(_b)
+            adopt(i, ::{{detail}}::interface_tag{}, _vtable, _t, _b{{if pmr}}, _mr{{end}}{{if tag}}, _tag{{end}});
+        return i;
+    }
+
+    bool expired() const noexcept { return !_b || _b->count.load(::std::memory_order_relaxed) == 0; }
+
+    void reset() noexcept
+    {
+        weak_interface empty;
+        swap(*this, empty);
+    }
+
+    friend void swap(weak_interface& x, weak_interface& y) noexcept
+    {
+        ::std::swap(x._b, y._b);
+        ::std::swap(x._t, y._t);
+        ::std::swap(x._vtable, y._vtable);
+{{- if pmr}}
+        ::std::swap(x._mr, y._mr);
+{{- end}}
+{{- if tag}}
+        ::std::swap(x._tag, y._tag);
+{{- end}}
+    }
+
+private:
+    ::{{detail}}::shared_block* _b = nullptr;
+    const ::{{detail}}::thunk* _t = nullptr;
+    const void* _vtable = nullptr;
+{{- if pmr}}
+    ::std::pmr::memory_resource* _mr = nullptr;
+{{- end}}
+{{- if tag}}
+    ::std::uint32_t _tag = 0;
+{{- end}}
+};
+`
+
+// range_iterator is the iterator of a range of the manifest, between its cursor and range interfaces.
+// excluded_str stands in for the macros of the numbers of methods left out by -only. They fail to
+// compile with a message naming the number and -only, rather than an error within the dispatch.
+var excluded_str = `
+// Interfaces of these numbers of methods were left out by -only.
+namespace {{if visibility}}INTERFACE_VISIBILITY {{end}}{{detail}}
+{
+{{- range .Arities}}
+    template<typename... T>
+    struct excluded_{{.}}
+    {
+        static_assert(sizeof...(T) != 0, "Interfaces of {{.}} method{{if ne . 1}}s{{end}} aren't generated, add {{.}} to -only of the generator.");
+        using type = interface_tag;
+    };
+{{- end}}
+}
+{{range $v := .Macros}}
+{{- range $.Arities}}
+#define {{$v}}_{{.}}(NAME, ...) class NAME : ::{{detail}}::excluded_{{.}}<>::type {}
+{{- end}}
+{{- end}}
+`
+
+// excluded is the data for expanding excluded_str.
+type excluded struct {
+	Macros  []string
+	Arities []int
+}
+
+var range_iterator = `// Iterator of {{.Name}}, holding any iterator whose elements convert to {{.Reference}} in a {{.Name}}_cursor.
+// Iterators compare equal if they hold iterators of the same type comparing equal, or are both empty.
+class {{exported}}{{.Name}}_iterator
+{
+    // Adapts the iterator It to the methods of {{.Name}}_cursor.
+    template<typename It>
+    struct adapter
+    {
+        explicit adapter(It i) : it(::std::move(i)) {}
+
+        {{.Reference}} get() const { return *it; }
+        void next() { ++it; }
+        bool equal(const {{.Name}}_cursor& c) const
+        {
+            auto p = c.template get_if<adapter>();
+            return p && p->it == it;
+        }
+
+        It it;
+    };
+
+public:
+    using iterator_category = ::std::input_iterator_tag;
+    using value_type = ::std::remove_cv_t<::std::remove_reference_t<{{.Reference}}>>;
+    using difference_type = ::std::ptrdiff_t;
+    using pointer = void;
+    using reference = {{.Reference}};
+
+    {{.Name}}_iterator() = default;
+
+    // Converts from the iterators returned by begin and end of the objects held by {{.Name}}.
+    template<typename It, ::std::enable_if_t<!::std::is_same_v<::std::decay_t<It>, {{.Name}}_iterator> &&
+                                             ::std::is_convertible_v<decltype(*::std::declval<It&>()), reference>, bool> = false>
+    {{.Name}}_iterator(It it) : _cursor(::make_interface<{{.Name}}_cursor, adapter<It>>(::std::move(it))) {}
+
+    reference operator*() const { return _cursor.get(); }
+
+    {{.Name}}_iterator& operator++()
+    {
+        _cursor.next();
+        return *this;
+    }
+
+    {{.Name}}_iterator operator++(int)
+    {
+        auto tmp = *this;
+        ++*this;
+        return tmp;
+    }
+
+    friend bool operator==(const {{.Name}}_iterator& x, const {{.Name}}_iterator& y)
+    {
+        if(!x._cursor || !y._cursor)
+            return !x._cursor == !y._cursor;
+        return x._cursor.equal(y._cursor);
+    }
+
+    friend bool operator!=(const {{.Name}}_iterator& x, const {{.Name}}_iterator& y) { return !(x == y); }
+
+private:
+    {{.Name}}_cursor _cursor;
+};`
+
+// variant is a flavour of interface, each with its own public macro.
+type variant struct {
+	Macro    string
+	Copyable bool
+	// Callable interfaces have a single function call operator instead of named methods.
+	Callable bool
+	// Free interfaces call non-member functions found by ADL, and expose them as hidden friends.
+	Free bool
+	// Shared interfaces share a single heap allocated object among copies.
+	Shared bool
+	// Default interfaces take a default for each method, called with objects not providing it.
+	Default bool
+	// Views refer to objects without owning them, expanded from view_str.
+	View bool
+}
+
+var variants = []variant{
+	{"INTERFACE", true, false, false, false, false, false},
+	// Move-only interfaces never copy, allowing move-only types to be stored by value.
+	{"INTERFACE_MOVE", false, false, false, false, false, false},
+	{"INTERFACE_CALLABLE", true, true, false, false, false, false},
+	{"INTERFACE_FREE", true, false, true, false, false, false},
+	{"INTERFACE_SHARED", true, false, false, true, false, false},
+	{"INTERFACE_DEFAULT", true, false, false, false, true, false},
+	{"INTERFACE_VIEW", true, false, false, false, false, true},
+}
+
+// method is the data for expanding a single method in interface_str.
+type method struct {
+	Index int
+	// Macro parameters naming the method.
+	Params string
+	// Name of the member function.
+	Name string
+	// Friend function fetching the type erased function from the vtable.
+	Getter string
+	// Factory for the type erased function.
+	Factory string
+	// Static function declared with the parameters of the signature, overloaded by methods sharing a name.
+	Selector string
+	// Appended to the object to call the method.
+	Call string
+	// Called as a non-member function taking the object first.
+	Free bool
+	// Macro parameter called with the object and the arguments if the object doesn't provide the method.
+	Default string
+	// Default arguments appended when omitting one, two, ... trailing arguments, only given by -manifest.
+	Defaults [][]string
+}
+
+func newMethod(v variant, i int) method {
+	if v.Callable {
+		return method{i, "SIGNATURE0", "operator()", "get_call_operator", "call_operator_factory", "call_operator_select", "", false, "", nil}
+	}
+	call := fmt.Sprintf(".METHOD_NAME%d", i)
+	if v.Free {
+		call = ""
+	}
+	params := fmt.Sprintf("SIGNATURE%d, METHOD_NAME%d", i, i)
+	def := ""
+	if v.Default {
+		def = fmt.Sprintf("DEFAULT%d", i)
+		params += ", " + def
+	}
+	return method{
+		i,
+		params,
+		fmt.Sprintf("METHOD_NAME%d", i),
+		fmt.Sprintf("get_##METHOD_NAME%d", i),
+		fmt.Sprintf("METHOD_NAME%d##_%d_factory", i, i),
+		fmt.Sprintf("METHOD_NAME%d##_select", i),
+		call,
+		v.Free,
+		def,
+		nil,
+	}
+}
+
+// arity is the data for expanding interface_str.
+type arity struct {
+	variant
+	Methods []method
+}
+
+// dispatch is the data for expanding the public macros in footer.
+type dispatch struct {
+	Macro string
+	// Callable interfaces have a single arity and need no dispatch.
+	Callable bool
+	// Default interfaces dispatch on three arguments per method.
+	Default bool
+	View    bool
+	Arities []int
+}
+
+// manifest is the file given by -manifest.
+type manifest struct {
+	Interfaces []namedInterface `json:"interfaces"`
+}
+
+// namedInterface is an interface of the manifest, generated as a class rather than a macro.
+type namedInterface struct {
+	Name string `json:"name"`
+	// Namespace enclosing the class, which may be nested, defaults to none.
+	Namespace string `json:"namespace"`
+	// Macro of the variant, defaults to INTERFACE.
+	Variant string        `json:"variant"`
+	Methods []namedMethod `json:"methods"`
+	// Element type of a range, generating the interfaces NAME_cursor and NAME, and the class
+	// NAME_iterator, instead of methods.
+	Range string `json:"range"`
+}
+
+// namedMethod holds the macro arguments of a method, the name is omitted for INTERFACE_CALLABLE.
+type namedMethod struct {
+	Signature string `json:"signature"`
+	Name      string `json:"name"`
+	Default   string `json:"default"`
+	// Default arguments of the trailing parameters, evaluated at each call omitting them.
+	Defaults []string `json:"defaults"`
+}
+
+// maxN bounds -N, since the header grows quadratically with it.
+const maxN = 64
+
+var N = flag.Int("N", 8, "maximum number of methods in interface, at most 64")
+var output = flag.String("output", "", "file to write the header to, defaults to stdout")
+var detailNamespace = flag.String("detail-namespace", "interface_detail", "namespace holding the implementation details")
+var guard = flag.String("guard", "", "include guard, either pragma for #pragma once or a macro name, defaults to none")
+var rtti = flag.Bool("rtti", false, "emit target_type, which requires RTTI")
+var concepts = flag.Bool("concepts", false, "emit the implements concept and constrain conversions on it, which requires C++20")
+var comparable = flag.Bool("comparable", false, "emit operator<=> comparing held objects by value, which requires C++20")
+var pmr = flag.Bool("pmr", false, "allocate objects that aren't stored inline from a std::pmr::memory_resource given on construction")
+var tag = flag.Bool("tag", false, "store a std::uint32_t tag in each interface, given on construction and returned by tag()")
+var doc = flag.Bool("doc", false, "annotate the public members of the generated classes with Doxygen comments")
+var reflect = flag.Bool("reflect", false, "emit method_table naming the methods of each interface, and method_index looking them up by name")
+var equality = flag.Bool("equality", false, "compare held objects of the same type with their operator== in operator== of interfaces")
+var abi = flag.Bool("abi-assert", false, "assert the size and alignment of each interface as laid out by the generator, to catch changes to the ABI")
+var refs = flag.Bool("ref-qualifiers", false, "accept & and && qualified signatures, qualifying every method of the interface by its value category")
+var memberTarget = flag.Bool("member-target", false, "make target a member function template rather than a friend found by ADL")
+var boundary = flag.Bool("noexcept-boundary", false, "catch exceptions thrown by held objects within the methods of interfaces, reporting them by interface_error")
+var debugChecks = flag.Bool("debug-checks", false, "assert the invariant of the pointers of interfaces on calls and copies, and poison them on destruction, unless NDEBUG is defined")
+var checked = flag.Bool("checked", false, "throw bad_interface_call when calling a method of an empty interface")
+var stream = flag.String("stream", "", "name of a const method taking std::ostream&, which operator<< of interfaces with it calls")
+var sbo = flag.Int("sbo", 16, "size in bytes of the buffer storing small objects without allocation, 0 disables it")
+var defaultMoveOnly = flag.Bool("default-move-only", false, "make INTERFACE move-only like INTERFACE_MOVE, for projects never copying interfaces")
+var relocatable = flag.Bool("relocatable", false, "mark interfaces as relocatable by copying their bytes, for folly::IsRelocatable, which requires -sbo=0")
+var split = flag.Bool("split", false, "write the implementation details and the macros to separate headers, see -detail-out and -macro-out")
+var detailOut = flag.String("detail-out", "", "file to write the implementation details to with -split")
+var macroOut = flag.String("macro-out", "", "file to write the macros to with -split, which includes -detail-out")
+var banner = flag.String("banner", "", "text written as comments at the top of each output file, or @file to read it from file")
+var manifestPath = flag.String("manifest", "", "JSON file of named interfaces to generate as classes after the macros")
+var includes = headerList{}
+var golden = flag.String("golden", "", "file to compare the header the other flags would generate to, instead of writing it, failing if they differ")
+var selftest = flag.Bool("selftest", false, "compile and run a sample program against the header generated for the other flags, instead of writing it")
+var cxx = flag.String("cxx", "", "compiler command for -selftest, defaults to $CXX or c++")
+var minimal = flag.Bool("minimal", false, "leave out the exposition of the implementation and comment lines, for embedding the header in another")
+var visibility = flag.String("visibility", "", "attribute of the implementation details, such as __attribute__((visibility(\"default\"))), overridable by INTERFACE_VISIBILITY")
+var visibilityClasses = flag.Bool("visibility-classes", false, "also give the generated interface classes the attribute of -visibility")
+var unique = flag.String("unique", "line", "naming of anonymous interfaces, line or counter to tell apart those on the same line")
+var prefix = flag.String("prefix", "INTERFACE", "name of the INTERFACE macro, which the other macros are named after, such as PREFIX_MOVE and GET_PREFIX_FROM")
+var module = flag.String("module", "", "name of a C++20 module to write instead of a header, exporting the -manifest interfaces, which is experimental")
+var onlyArities = flag.String("only", "", "comma separated numbers of methods to emit macros for, defaults to all up to N")
+
+// macroName matches the names of the macros of the header, which -prefix renames.
+var macroName = regexp.MustCompile(`\b(GET_)?INTERFACE(_[A-Z0-9_]+)?\b`)
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+var moduleName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+var namespace = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(::[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// parameter matches the macro parameters within interface_str, replaced like the preprocessor would.
+// stringized matches those turned into string literals, which are method names and signatures,
+// and the empty arguments of interfaces without methods.
+var stringized = regexp.MustCompile(`(^|[^#])#(METHOD_NAME[0-9]+|SIGNATURE[0-9]+|__VA_ARGS__)\b`)
+var parameter = regexp.MustCompile(`\b(NAME|SIGNATURE[0-9]+|METHOD_NAME[0-9]+|DEFAULT[0-9]+|DEFAULT_ARG[0-9]+_[0-9]+)\b`)
+
+func init() {
+	flag.StringVar(output, "o", "", "shorthand for -output")
+	flag.Var(&includes, "include", "header to #include after the built-in ones, as <name> or \"name\", may be repeated")
+}
+
+// funcs exposes the generator options to the templates.
+func funcs() template.FuncMap {
+	return template.FuncMap{
+		"detail":       func() string { return *detailNamespace },
+		"sbo":          func() int { return *sbo },
+		"relocatable":  func() bool { return *relocatable },
+		"moveOnly":     func() bool { return *defaultMoveOnly },
+		"rtti":         func() bool { return *rtti },
+		"concepts":     func() bool { return *concepts },
+		"comparable":   func() bool { return *comparable },
+		"equality":     func() bool { return *equality && !*comparable },
+		"abi":          func() bool { return *abi },
+		"refs":         refQualifiers,
+		"memberTarget": func() bool { return *memberTarget },
+		"checked":      func() bool { return *checked },
+		"debugChecks":  func() bool { return *debugChecks },
+		"boundary":     func() bool { return *boundary },
+		"pmr":          func() bool { return *pmr },
+		"tag":          func() bool { return *tag },
+		"stream":       func() string { return *stream },
+		"includes":     func() []string { return includes },
+		"doc":          func() bool { return *doc },
+		"counter":      func() bool { return *unique == "counter" },
+		"minimal":      func() bool { return *minimal },
+		"visibility":   func() string { return *visibility },
+		"module":       func() string { return *module },
+		"export":       export,
+		"exported":     exported,
+		"reflect":      func() bool { return *reflect },
+		"reserved":     reserved,
+	}
+}
+
+// reserved returns the member functions of interfaces, or of views, as a braced list of string literals.
+// Methods may not be named after them, which the interfaces assert.
+// clone isn't among them, as interfaces with a method named clone leave it out instead, see Example 5.
+func reserved(view bool) string {
+	names := []string{"underlying_address", "has_reference_semantics"}
+	if !view {
+		names = append(names, "get_if", "holds", "type_id", "reset", "emplace")
+		if *memberTarget {
+			names = append(names, "target")
+		}
+		if *rtti {
+			names = append(names, "target_type")
+		}
+		if *pmr {
+			names = append(names, "resource")
+		}
+		if *tag {
+			names = append(names, "tag")
+		}
+	}
+	return `{"` + strings.Join(names, `", "`) + `"}`
+}
+
+// export returns the keyword exporting public declarations from the module unit of -module.
+func export() string {
+	if *module == "" {
+		return ""
+	}
+	return "export "
+}
+
+// exported returns the attribute preceding the names of interface classes with -visibility-classes.
+func exported() string {
+	if *visibility == "" || !*visibilityClasses {
+		return ""
+	}
+	return "INTERFACE_VISIBILITY "
+}
+
+// refQualifier is a qualification of the methods of interfaces with -ref-qualifiers, selecting signatures
+// with the tag of its value category and calling them with the pointer to the object for it.
+type refQualifier struct {
+	Qualifier string
+	Tag       string
+	Pointer   string
+}
+
+// refQualifiers returns the qualifications each method is generated for, or none without -ref-qualifiers.
+func refQualifiers() []refQualifier {
+	if !*refs {
+		return nil
+	}
+	d := "::" + *detailNamespace
+	return []refQualifier{
+		{"&", "::std::declval<" + d + "::mutable_tag&>()", "void*"},
+		{"&&", "::std::declval<" + d + "::mutable_tag>()", d + "::rvalue*"},
+		{"const&", "::std::declval<const " + d + "::const_tag&>()", "const void*"},
+		{"const&&", "::std::declval<const " + d + "::const_tag>()", "const " + d + "::rvalue*"},
+	}
+}
+
+// headerList collects the headers given by repeating -include, bare names are taken as <name>.
+type headerList []string
+
+func (h *headerList) String() string { return strings.Join(*h, ",") }
+
+// Set rejects headers whose delimiters don't match, which would emit an unterminated #include.
+func (h *headerList) Set(value string) error {
+	header, name := value, value
+	switch {
+	case strings.HasPrefix(value, "<"):
+		name = strings.TrimSuffix(value[1:], ">")
+	case strings.HasPrefix(value, `"`):
+		name = strings.TrimSuffix(value[1:], `"`)
+	default:
+		header = "<" + value + ">"
+	}
+	if name == "" || len(name) != len(header)-2 || strings.ContainsAny(name, "<>\"\r\n") {
+		return fmt.Errorf("invalid header %q, must be name, <name> or \"name\"", value)
+	}
+	*h = append(*h, header)
+	return nil
+}
+
+// parseOnly returns the set of arities listed in -only, or nil for all of them.
+func parseOnly(value string, n int) (map[int]bool, error) {
+	if value == "" {
+		return nil, nil
+	}
+	only := map[int]bool{}
+	for _, f := range strings.Split(value, ",") {
+		k, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil || k < 1 || k > n {
+			return nil, fmt.Errorf("-only must list numbers of methods between 1 and N, got %q", f)
+		}
+		only[k] = true
+	}
+	return only, nil
+}
+
+// loadBanner returns the text of -banner, read from the file following @ if it starts with one.
+func loadBanner(value string) (string, error) {
+	if !strings.HasPrefix(value, "@") {
+		return value, nil
+	}
+	b, err := ioutil.ReadFile(value[1:])
+	return string(b), err
+}
+
+// writeBanner writes each line of text as a comment, followed by an empty line, if there is any text.
+// Trailing whitespace, including that of empty lines, is stripped by the normalizer.
+func writeBanner(w io.Writer, text string) error {
+	text = strings.TrimRight(text, "\r\n")
+	if text == "" {
+		return nil
+	}
+	for _, l := range strings.Split(text, "\n") {
+		if _, err := fmt.Fprintln(w, "// "+l); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// openGuard writes the start of the include guard name, if any.
+func openGuard(w io.Writer, name string) error {
+	switch name {
+	case "":
+	case "pragma":
+		if _, err := fmt.Fprint(w, "#pragma once\n\n"); err != nil {
+			return err
+		}
+	default:
+		if _, err := fmt.Fprintf(w, "#ifndef %s\n#define %s\n\n", name, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// closeGuard writes the end of the include guard name, if any.
+func closeGuard(w io.Writer, name string) error {
+	if name != "" && name != "pragma" {
+		if _, err := fmt.Fprintf(w, "#endif // %s\n", name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// detailGuard is the include guard of the implementation details with -split.
+func detailGuard() string {
+	if *guard == "" || *guard == "pragma" {
+		return *guard
+	}
+	return *guard + "_DETAIL"
+}
+
+// generateDetail writes the implementation details, which don't depend on the number of methods.
+func generateDetail(w io.Writer) error {
+	return template.Must(template.New("").Funcs(funcs()).Parse(header)).Execute(w, nil)
+}
+
+// macroTemplate parses interface_str, and view_str as the template "view" sharing its definitions.
+func macroTemplate() *template.Template {
+	tmp := template.Must(template.New("").Funcs(funcs()).Parse(interface_str))
+	template.Must(tmp.New("view").Parse(view_str))
+	return tmp
+}
+
+// macroFor returns the template of tmp expanding the macros of v.
+func macroFor(tmp *template.Template, v variant) *template.Template {
+	if v.View {
+		return tmp.Lookup("view")
+	}
+	return tmp
+}
+
+// generateMacros writes the macros for interfaces of up to n methods.
+// If only isn't nil, the macros of other arities are left out, while dispatch still covers all of them.
+func generateMacros(w io.Writer, n int, only map[int]bool) error {
+	tmp := macroTemplate()
+	for _, v := range variants {
+		// Modules can't export macros, and expand the interfaces of the manifest without them.
+		if *module != "" {
+			break
+		}
+		s := []method{}
+		// Interfaces without methods only hold objects, and are dispatched to by the single empty
+		// argument of __VA_ARGS__. They are generated regardless of -only.
+		if !v.Callable {
+			if err := macroFor(tmp, v).Execute(w, arity{v, s}); err != nil {
+				return err
+			}
+		}
+		for i := 0; i < n; i++ {
+			s = append(s, newMethod(v, i))
+			if only != nil && !only[i+1] && !v.Callable {
+				continue
+			}
+			if err := macroFor(tmp, v).Execute(w, arity{v, s}); err != nil {
+				return err
+			}
+			// Callable interfaces only have the call operator.
+			if v.Callable {
+				break
+			}
+		}
+	}
+
+	if only != nil && *module == "" {
+		e := excluded{}
+		for _, v := range variants {
+			if !v.Callable {
+				e.Macros = append(e.Macros, v.Macro)
+			}
+		}
+		for i := 1; i <= n; i++ {
+			if !only[i] {
+				e.Arities = append(e.Arities, i)
+			}
+		}
+		if len(e.Arities) != 0 {
+			if err := template.Must(template.New("").Funcs(funcs()).Parse(excluded_str)).Execute(w, e); err != nil {
+				return err
+			}
+		}
+	}
+
+	r := []int{}
+	for i := 0; i < n; i++ {
+		r = append(r, n-i)
+	}
+	d := []dispatch{}
+	for _, v := range variants {
+		d = append(d, dispatch{v.Macro, v.Callable, v.Default, v.View, r})
+	}
+	return template.Must(template.New("").Funcs(funcs()).Parse(footer)).Execute(w, d)
+}
+
+// findVariant returns the variant with the given macro.
+func findVariant(macro string) (variant, bool) {
+	for _, v := range variants {
+		if v.Macro == macro {
+			return v, true
+		}
+	}
+	return variant{}, false
+}
+
+// loadManifest reads and validates the manifest at path.
+func loadManifest(path string) ([]namedInterface, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var m manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	for k := range m.Interfaces {
+		i := &m.Interfaces[k]
+		if i.Variant == "" {
+			i.Variant = "INTERFACE"
+		}
+		if err := i.validate(); err != nil {
+			return nil, fmt.Errorf("%s: interface %d: %v", path, k, err)
+		}
+	}
+	return m.Interfaces, nil
+}
+
+// validate reports an error for arguments the macro of the variant wouldn't accept.
+func (i namedInterface) validate() error {
+	if !identifier.MatchString(i.Name) {
+		return fmt.Errorf("name %q is not an identifier", i.Name)
+	}
+	if i.Namespace != "" && !namespace.MatchString(i.Namespace) {
+		return fmt.Errorf("namespace %q is not a namespace name", i.Namespace)
+	}
+	if i.Range != "" {
+		if i.Variant != "INTERFACE" || len(i.Methods) > 0 {
+			return fmt.Errorf("range %s takes neither a variant nor methods", i.Name)
+		}
+		if *defaultMoveOnly {
+			return fmt.Errorf("range %s has copyable iterators, which -default-move-only leaves out", i.Name)
+		}
+		return nil
+	}
+	v, ok := findVariant(i.Variant)
+	if !ok {
+		return fmt.Errorf("unknown variant %q", i.Variant)
+	}
+	if v.Callable && len(i.Methods) != 1 {
+		return fmt.Errorf("%s takes a single signature", v.Macro)
+	}
+	for k, m := range i.Methods {
+		switch {
+		case m.Signature == "":
+			return fmt.Errorf("method %d has no signature", k)
+		case v.Callable && m.Name != "":
+			return fmt.Errorf("method %d of %s has a name", k, v.Macro)
+		case !v.Callable && !identifier.MatchString(m.Name):
+			return fmt.Errorf("method %d name %q is not an identifier", k, m.Name)
+		case v.Default && m.Default == "":
+			return fmt.Errorf("method %d has no default", k)
+		case !v.Default && m.Default != "":
+			return fmt.Errorf("method %d of %s has a default", k, v.Macro)
+		case v.Free && len(m.Defaults) > 0:
+			return fmt.Errorf("method %d of %s has default arguments", k, v.Macro)
+		}
+		for _, d := range m.Defaults {
+			if strings.TrimSpace(d) == "" {
+				return fmt.Errorf("method %d has an empty default argument", k)
+			}
+		}
+	}
+	return nil
+}
+
+// generateNamed writes the interfaces as classes, by expanding the macro of their variant and arity.
+func generateNamed(w io.Writer, interfaces []namedInterface) error {
+	tmp := macroTemplate()
+	iterator := template.Must(template.New("").Funcs(funcs()).Parse(range_iterator))
+	for _, i := range interfaces {
+		if i.Range != "" {
+			if err := generateRange(w, iterator, i); err != nil {
+				return err
+			}
+			continue
+		}
+		v, _ := findVariant(i.Variant)
+		s := []method{}
+		args := map[string]string{"NAME": i.Name}
+		for k, m := range i.Methods {
+			s = append(s, newMethod(v, k))
+			for j, d := range m.Defaults {
+				args[fmt.Sprintf("DEFAULT_ARG%d_%d", k, j)] = d
+			}
+			// Omitting n arguments appends the last n defaults.
+			for n := 1; n <= len(m.Defaults); n++ {
+				d := []string{}
+				for j := len(m.Defaults) - n; j < len(m.Defaults); j++ {
+					d = append(d, fmt.Sprintf("DEFAULT_ARG%d_%d", k, j))
+				}
+				s[k].Defaults = append(s[k].Defaults, d)
+			}
+			args[fmt.Sprintf("SIGNATURE%d", k)] = m.Signature
+			args[fmt.Sprintf("METHOD_NAME%d", k)] = m.Name
+			args[fmt.Sprintf("DEFAULT%d", k)] = m.Default
+		}
+		var b bytes.Buffer
+		if err := macroFor(tmp, v).Execute(&b, arity{v, s}); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "\n%s\n", expand(b.String(), args, i.Namespace)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generateRange writes the interfaces of the range r, whose iterators hold a cursor interface
+// erasing the iterators of the objects, which are converted to them when returned by begin and end.
+func generateRange(w io.Writer, iterator *template.Template, r namedInterface) error {
+	cursor := namedInterface{Name: r.Name + "_cursor", Namespace: r.Namespace, Variant: "INTERFACE", Methods: []namedMethod{
+		{Signature: r.Range + "() const", Name: "get"},
+		{Signature: "void()", Name: "next"},
+		{Signature: "bool(const " + r.Name + "_cursor&) const", Name: "equal"},
+	}}
+	if err := generateNamed(w, []namedInterface{cursor}); err != nil {
+		return err
+	}
+	var b bytes.Buffer
+	if err := iterator.Execute(&b, struct{ Name, Reference string }{r.Name, r.Range}); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "\n%s\n", inNamespace(b.String(), r.Namespace)); err != nil {
+		return err
+	}
+	it := r.Name + "_iterator() const"
+	return generateNamed(w, []namedInterface{{Name: r.Name, Namespace: r.Namespace, Variant: "INTERFACE", Methods: []namedMethod{
+		{Signature: it, Name: "begin"},
+		{Signature: it, Name: "end"},
+	}}})
+}
+
+// expand turns the macro definition into the class it defines, replacing the parameters with args.
+// Only one macro is defined, so the first line is the #define and the rest its body.
+func expand(macro string, args map[string]string, ns string) string {
+	lines := strings.Split(strings.TrimSpace(macro), "\n")[1:]
+	for k, l := range lines {
+		l = strings.TrimSuffix(l, "\\")
+		l = stringized.ReplaceAllStringFunc(l, func(p string) string {
+			m := stringized.FindStringSubmatch(p)
+			return m[1] + `"` + args[m[2]] + `"`
+		})
+		l = parameter.ReplaceAllStringFunc(l, func(p string) string { return args[p] })
+		lines[k] = strings.Replace(l, "##", "", -1)
+	}
+	return inNamespace(strings.Join(lines, "\n")+";", ns)
+}
+
+// inNamespace encloses class in the namespace ns, unless ns is empty, exported with -module.
+func inNamespace(class, ns string) string {
+	if ns == "" {
+		return export() + class
+	}
+	return export() + "namespace " + ns + "\n{\n" + class + "\n}"
+}
+
+// generate writes the complete header for interfaces of up to n methods, followed by the named interfaces.
+func generate(w io.Writer, n int, only map[int]bool, interfaces []namedInterface, banner string) error {
+	if err := writeBanner(w, banner); err != nil {
+		return err
+	}
+	if err := openGuard(w, *guard); err != nil {
+		return err
+	}
+	err := filtered(w, func(w io.Writer) error {
+		if err := generateDetail(w); err != nil {
+			return err
+		}
+		if err := generateMacros(w, n, only); err != nil {
+			return err
+		}
+		return generateNamed(w, interfaces)
+	})
+	if err != nil {
+		return err
+	}
+	return closeGuard(w, *guard)
+}
+
+// generateSplit writes the macro header for interfaces of up to n methods and the named interfaces,
+// including the detail header at include.
+func generateSplit(w io.Writer, include string, n int, only map[int]bool, interfaces []namedInterface, banner string) error {
+	if err := writeBanner(w, banner); err != nil {
+		return err
+	}
+	if err := openGuard(w, *guard); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "// DO NOT modify, this is a machine generated file.\n// See impl/README for details.\n\n#include \"%s\"\n", include); err != nil {
+		return err
+	}
+	err := filtered(w, func(w io.Writer) error {
+		if err := generateMacros(w, n, only); err != nil {
+			return err
+		}
+		return generateNamed(w, interfaces)
+	})
+	if err != nil {
+		return err
+	}
+	return closeGuard(w, *guard)
+}
+
+// writeFile calls gen on path, or on stdout if path is empty.
+// An existing file is truncated so each run fully rewrites it.
+func writeFile(path string, gen func(io.Writer) error) error {
+	if path == "" {
+		return buffered(os.Stdout, gen)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = buffered(f, gen)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// buffered calls gen on a buffer over w, since templates make many small writes.
+// Errors of any write are reported by the final flush.
+func buffered(w io.Writer, gen func(io.Writer) error) error {
+	b := bufio.NewWriter(w)
+	n := &normalizer{w: b}
+	if err := gen(n); err != nil {
+		return err
+	}
+	if err := n.flush(); err != nil {
+		return err
+	}
+	return b.Flush()
+}
+
+// normalizer strips trailing whitespace from every line written through it,
+// including before the backslash continuing a macro, so that template edits
+// don't leave spacing that differs between otherwise identical lines.
+type normalizer struct {
+	w    io.Writer
+	line []byte
+}
+
+func (n *normalizer) Write(p []byte) (int, error) {
+	for _, c := range p {
+		if c != '\n' {
+			n.line = append(n.line, c)
+			continue
+		}
+		n.line = append(trim(n.line), '\n')
+		if err := n.flush(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// flush writes the pending line as is.
+func (n *normalizer) flush() error {
+	_, err := n.w.Write(n.line)
+	n.line = n.line[:0]
+	return err
+}
+
+// trim removes trailing whitespace, keeping a trailing backslash right after the last token.
+func trim(line []byte) []byte {
+	line = bytes.TrimRight(line, " \t\r")
+	if bytes.HasSuffix(line, []byte("\\")) {
+		line = append(bytes.TrimRight(line[:len(line)-1], " \t"), '\\')
+	}
+	return line
+}
+
+// filtered calls gen on w through the filters of the flags, renaming the macros with -prefix and
+// leaving out comments with -minimal.
+func filtered(w io.Writer, gen func(io.Writer) error) error {
+	return uncommented(w, func(w io.Writer) error { return renamed(w, gen) })
+}
+
+// renamed calls gen on w, through a renamer unless -prefix is the default.
+func renamed(w io.Writer, gen func(io.Writer) error) error {
+	if *prefix == "INTERFACE" {
+		return gen(w)
+	}
+	r := &renamer{w: w}
+	if err := gen(r); err != nil {
+		return err
+	}
+	return r.flush()
+}
+
+// renamer replaces INTERFACE with -prefix in the names of the macros written through it, including
+// those only mentioned in comments and messages, such as GET_INTERFACE_FROM or INTERFACE_VISIBILITY.
+// Lines are replaced whole, since names may be split across the writes of templates.
+type renamer struct {
+	w    io.Writer
+	line []byte
+}
+
+func (r *renamer) Write(p []byte) (int, error) {
+	for _, c := range p {
+		r.line = append(r.line, c)
+		if c != '\n' {
+			continue
+		}
+		if err := r.flush(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// flush writes the pending line renamed.
+func (r *renamer) flush() error {
+	_, err := r.w.Write(macroName.ReplaceAll(r.line, []byte("${1}"+*prefix+"${2}")))
+	r.line = r.line[:0]
+	return err
+}
+
+// uncommented calls gen on w, through an uncommenter with -minimal.
+func uncommented(w io.Writer, gen func(io.Writer) error) error {
+	if !*minimal {
+		return gen(w)
+	}
+	u := &uncommenter{w: w}
+	if err := gen(u); err != nil {
+		return err
+	}
+	return u.flush()
+}
+
+// uncommenter drops the lines written through it consisting only of a // comment, other than the
+// leading ones holding the notice of the generated file, and the blank lines left repeated by them.
+// Comments within macros are kept, as they are either absent or the Doxygen comments of -doc.
+type uncommenter struct {
+	w     io.Writer
+	line  []byte
+	body  bool
+	blank bool
+}
+
+func (u *uncommenter) Write(p []byte) (int, error) {
+	for _, c := range p {
+		u.line = append(u.line, c)
+		if c != '\n' {
+			continue
+		}
+		l := bytes.TrimSpace(u.line)
+		comment := bytes.HasPrefix(l, []byte("//"))
+		switch {
+		case comment && u.body, len(l) == 0 && u.blank:
+			u.line = u.line[:0]
+			continue
+		case !comment && len(l) > 0:
+			u.body = true
+		}
+		u.blank = len(l) == 0
+		if err := u.flush(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// flush writes the pending line as is.
+func (u *uncommenter) flush() error {
+	_, err := u.w.Write(u.line)
+	u.line = u.line[:0]
+	return err
+}
+
+// run writes the header to path, or to stdout if path is empty.
+func run(path string, n int, only map[int]bool, interfaces []namedInterface, banner string) error {
+	return writeFile(path, func(w io.Writer) error { return generate(w, n, only, interfaces, banner) })
+}
+
+// runSplit writes the implementation details to detail, and the macros to macro.
+// The macro header includes the detail header by its path relative to the macro header.
+func runSplit(detail, macro string, n int, only map[int]bool, interfaces []namedInterface, banner string) error {
+	include, err := filepath.Rel(filepath.Dir(macro), detail)
+	if err != nil {
+		return err
+	}
+	err = writeFile(detail, func(w io.Writer) error {
+		if err := writeBanner(w, banner); err != nil {
+			return err
+		}
+		if err := openGuard(w, detailGuard()); err != nil {
+			return err
+		}
+		if err := filtered(w, generateDetail); err != nil {
+			return err
+		}
+		return closeGuard(w, detailGuard())
+	})
+	if err != nil {
+		return err
+	}
+	return writeFile(macro, func(w io.Writer) error {
+		return generateSplit(w, filepath.ToSlash(include), n, only, interfaces, banner)
+	})
+}
+
+// runGolden generates the header into memory and compares it to the file at path, reporting the first
+// line that differs, so that a header committed along with the generator is checked to match it.
+func runGolden(path string, n int, only map[int]bool, interfaces []namedInterface, banner string) error {
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var got bytes.Buffer
+	err = buffered(&got, func(w io.Writer) error { return generate(w, n, only, interfaces, banner) })
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(got.Bytes(), want) {
+		return nil
+	}
+
+	g, w := bytes.Split(got.Bytes(), []byte("\n")), bytes.Split(want, []byte("\n"))
+	line := 0
+	for line < len(g) && line < len(w) && bytes.Equal(g[line], w[line]) {
+		line++
+	}
+	return fmt.Errorf("%s:%d: differs from the generated header", path, line+1)
+}
+
+// selftestInterfaces lists the methods of the interfaces of selftestProgram by their numbers.
+// Methods are those of the interface I, and Superset those of S, which converts to I. Superset is
+// in reverse order so that methods are found by name rather than slot, and empty if S isn't generated.
+// Reversed are those of I in reverse order, for J converted from I in turn. Chain are the queries of
+// C following its two mutators, and of P following its pair of methods at, empty if no interface of at
+// least three methods is generated.
+// Members are the methods of the object held by all of them. Range is the class erasing ranges of
+// ints, empty if ranges aren't generated.
+type selftestInterfaces struct {
+	Methods  []int
+	Superset []int
+	Reversed []int
+	Chain    []int
+	Members  []int
+	Range    string
+}
+
+// selftestRange erases the ranges of selftestProgram, added to the interfaces of the manifest.
+var selftestRange = namedInterface{Name: "ints", Namespace: "selftest", Variant: "INTERFACE", Range: "const int&"}
+
+// selftestProgram exercises the macros of the header, with the methods f0 to fn-1 of the interface I.
+var selftestProgram = `#include "interface.hpp"
+#include <cstdio>
+#include <cstdlib>
+{{- if .Range}}
+#include <list>
+#include <vector>
+{{- end}}
+
+#define CHECK(c) if(!(c)) { std::puts("failed: " #c); return 1; }
+
+// Counts allocations of the global operator new, to check that operations don't allocate.
+static int allocations = 0;
+
+void* operator new(::std::size_t n)
+{
+    ++allocations;
+    if(void* p = ::std::malloc(n ? n : 1))
+        return p;
+    throw ::std::bad_alloc{};
+}
+void operator delete(void* p) noexcept { ::std::free(p); }
+void operator delete(void* p, ::std::size_t) noexcept { ::std::free(p); }
+
+struct A
+{
+    int n;
+{{- range .Members}}
+    int f{{.}}() const { return n + {{.}}; }
 {{- end}}
-#define INTERFACE_{{len .}}({{template "macro args" .}})\
-class INTERFACE_APPEND_LINE(interface__) : ::interface_detail::interface_tag\
-{\
-    using interface = INTERFACE_APPEND_LINE(interface__);\
-\
-    {{- range .}}
-    friend auto get_##METHOD_NAME{{.}}(const interface& i, ::interface_detail::interface_tag)\
-    {\
-        using std::get;\
-        return get<{{.}}>(i._vtable);\
-    }\
-\
-    template<typename T__>\
-    struct METHOD_NAME{{.}}##_{{.}}_factory\
-    {\
-        template<typename... Args__>\
-        static decltype(auto) call(void* p, Args__&&... as)\
-        {\
-            return ::interface_detail::as_object<T__>(p).METHOD_NAME{{.}}(::std::forward<Args__>(as)...);\
-        }\
-    };\
+    void set(int x) { n = x; }
+    int add(int x) { return n += x; }
+    // The overloads tell apart calls through const and non-const interfaces.
+    int& at(::std::size_t) { return n; }
+    const int& at(::std::size_t) const { return constant; }
+    static constexpr int constant = -1;
+};
+
+// Holds the methods of A, but is too large to be held without allocation.
+struct B : A
+{
+    char pad[256];
+};
+
+using I = INTERFACE({{range $k, $v := .Methods}}{{if $k}}, {{end}}int() const, f{{$v}}{{end}});
+using N = INTERFACE({{range $k, $v := .Methods}}{{if $k}}, {{end}}int() const{{if not $k}} noexcept(true){{end}}, f{{$v}}{{end}});
+INTERFACE_DEFINE_IN(selftest::geo, Shape, {{range $k, $v := .Methods}}{{if $k}}, {{end}}int() const, f{{$v}}{{end}});
+INTERFACE_DEFINE_IN(selftest::ui, Shape, {{range $k, $v := .Methods}}{{if $k}}, {{end}}int() const, f{{$v}}{{end}});
+{{- if .Superset}}
+using S = INTERFACE({{range $k, $v := .Superset}}{{if $k}}, {{end}}int() const, f{{$v}}{{end}});
+using J = INTERFACE({{range $k, $v := .Reversed}}{{if $k}}, {{end}}int() const, f{{$v}}{{end}});
+{{- end}}
+using F = INTERFACE_CALLABLE(int(int));
+using G = INTERFACE_CALLABLE(int(const I&) const);
+{{- if .Chain}}
+using C = INTERFACE(interface_self&(int), set, interface_self&(int), add{{range .Chain}}, int() const, f{{.}}{{end}});
+using P = INTERFACE(int&(::std::size_t), at, const int&(::std::size_t) const, at{{range .Chain}}, int() const, f{{.}}{{end}});
+{{- end}}
+using E = INTERFACE();
+using R = INTERFACE_CALLABLE(int(interface) const);
+{{- if refs}}
+using Q = INTERFACE({{range $k, $v := .Methods}}{{if $k}}, {{end}}int() {{if $k}}const{{else}}&&{{end}}, f{{$v}}{{end}});
+
+// Whether f0 may be called on a T, to check that methods qualified && aren't called on lvalues.
+template<typename T, typename = void>
+struct can_call_f0 : ::std::false_type {};
+template<typename T>
+struct can_call_f0<T, ::std::void_t<decltype(::std::declval<T>().f0())>> : ::std::true_type {};
+{{- end}}
+
+// Too large to be held without allocation.
+struct Large
+{
+    char pad[256];
+    int operator()(R r) const { return r ? 2 : 1; }
+};
+
+{{- if .Range}}
+// Collects the elements of any range of ints through the same erased type.
+::std::vector<int> collect(const {{.Range}}& r)
+{
+    ::std::vector<int> v;
+    for(int x : r)
+        v.push_back(x);
+    return v;
+}
+
+{{end -}}
+// Calls whichever form of target the header provides.
+template<typename T, typename J>
+auto held(J& j)
+{
+    return {{if memberTarget}}j.template target<T>(){{else}}target<T>(j){{end}};
+}
+
+int main()
+{
+    I i = A{1};
+    I j = A{2};
+    {{- range .Methods}}
+    CHECK(i.f{{.}}() == 1 + {{.}});
     {{- end}}
-\
-    friend auto fetch_ptr(const interface& i, ::interface_detail::interface_tag)\
-    {\
-        return i._ptr;\
-    }\
-\
-    friend auto&& fetch_thunk(const interface& i, ::interface_detail::interface_tag)\
-    {\
-        return i._t;\
-    }\
-\
-    template<typename I__>\
-    void construct(I__&& i)\
-    {\
-        if(!i)\
-            return;\
-\
-        auto p = fetch_ptr(i, ::interface_detail::interface_tag{});\
-        auto t = fetch_thunk(i, ::interface_detail::interface_tag{});\
-        auto buf = ::std::unique_ptr<::std::byte[]>(new ::std::byte[t->size]);\
-        if constexpr(::std::is_lvalue_reference_v<I__> || ::std::is_const_v<I__>)\
-            t->copy(buf.get(), p);\
-        else\
-            t->move(buf.get(), p);\
-        _ptr = ::std::launder(buf.get());\
-        buf.release();\
-        _t = t;\
-        _vtable = {\
-            {{- range .}}
-            get_##METHOD_NAME{{.}}(i, ::interface_detail::interface_tag{}),\
-            {{- end}}
-        };\
-    }\
-\
-public:\
-    INTERFACE_APPEND_LINE(interface__)() = default;\
-    INTERFACE_APPEND_LINE(interface__)(interface&& other) noexcept { swap(*this, other); }\
-    INTERFACE_APPEND_LINE(interface__)(const interface& other) { construct(other); }\
-    template<typename I__, ::std::enable_if_t<::interface_detail::is_interface_v<::std::decay_t<I__>>, bool> = false>\
-    INTERFACE_APPEND_LINE(interface__)(I__&& i)\
-    {\
-        construct(::std::forward<I__>(i));\
-    }\
-\
-\
-    template <typename T__, ::std::enable_if_t<!::interface_detail::is_interface_v<::std::decay_t<T__>>, bool> = false>\
-    INTERFACE_APPEND_LINE(interface__)(T__&& t)\
-    {\
-        using U__ = ::std::decay_t<T__>;\
-        static_assert(alignof(U__) <= __STDCPP_DEFAULT_NEW_ALIGNMENT__, "Doesn't support overaligned type.");\
-        static_assert(::std::is_constructible_v<U__, const U__&>, "Value semantics require the type be copy constructible.");\
-        auto buf = ::std::unique_ptr<::std::byte[]>(new ::std::byte[sizeof(U__)]);\
-        _ptr = new (buf.get()) U__{::std::forward<T__>(t)};\
-        buf.release();\
-        _t = ::interface_detail::get_thunk<U__>();\
-\
-        _vtable = {\
-            {{- range .}}
-            ::interface_detail::erasure_fn<SIGNATURE{{.}}, METHOD_NAME{{.}}##_{{.}}_factory<U__>>::value,\
-            {{- end}}
-        };\
-    }\
-\
-    ~INTERFACE_APPEND_LINE(interface__)()\
-    {\
-        if(_ptr)\
-            _t->destroy(_ptr);\
-        delete[] reinterpret_cast<::std::byte*>(_ptr);\
-    }\
-\
-    interface& operator=(const interface& other)\
-    {\
-        auto tmp = other;\
-        swap(*this, tmp);\
-        return *this;\
-    }\
-    interface& operator=(interface&& other) noexcept\
-    {\
-        auto tmp = ::std::move(other);\
-        swap(*this, tmp);\
-        return *this;\
-    }\
-\
-    {{- range .}}
-    template<typename... Args__>\
-    decltype(auto) METHOD_NAME{{.}}(Args__&&... as)\
-    {\
-        return get_##METHOD_NAME{{.}}(*this, ::interface_detail::interface_tag{})(_ptr, ::std::forward<Args__>(as)...);\
-    }\
+    CHECK(held<A>(i) && held<A>(i)->n == 1);
+    const I& ci = i;
+    A* first = held<A>(i);
+    static_assert(::std::is_same_v<decltype(held<const A&>(i)), const A*>, "Qualifiers are kept.");
+    static_assert(::std::is_same_v<decltype(held<volatile A>(i)), volatile A*>, "Qualifiers are kept.");
+    static_assert(::std::is_same_v<decltype(ci.get_if<A&>()), const A*>, "Constness of the interface is added.");
+    static_assert(::std::is_same_v<decltype(held<A>(ci)), const A*>, "Constness of the interface is added.");
+    static_assert(noexcept(::std::declval<const N&>().f0()){{if not boundary}} && !noexcept(ci.f0()){{end}}, "noexcept signatures give noexcept methods.");
+    CHECK(held<const A>(i) == first && held<A&>(i) == first && held<const A&>(i) == first && held<volatile A>(i) == first);
+    CHECK(held<A>(ci) == first && held<A&>(ci) == first && held<const A&>(ci) == first && held<volatile A>(ci) == first);
+    CHECK(i.get_if<const A>() == first && i.get_if<A&>() == first && i.get_if<const A&>() == first && i.get_if<volatile A>() == first);
+    CHECK(ci.get_if<A>() == first && ci.get_if<A&>() == first && ci.get_if<const A&>() == first && ci.get_if<volatile A>() == first);
+    swap(i, j);
+    CHECK(i.f0() == 2 && j.f0() == 1);
+    {{- if moveOnly}}
+    I k = ::std::move(i);
+    CHECK(k.f0() == 2 && !i);
+    i = ::std::move(k);
+    {{- else}}
+    I k = i;
+    CHECK(k.f0() == 2 && held<A>(k) != held<A>(i));
     {{- end}}
-\
-    template<typename T__>\
-    friend T__* target(interface&& i) noexcept\
-    {\
-        if(i._t == ::interface_detail::get_thunk<T__>())\
-            return reinterpret_cast<T__*>(i._ptr);\
-        else\
-            return nullptr;\
-    }\
-    template<typename T__>\
-    friend T__* target(interface& i) noexcept\
-    {\
-        if(i._t == ::interface_detail::get_thunk<T__>())\
-            return reinterpret_cast<T__*>(i._ptr);\
-        else\
-            return nullptr;\
-    }\
-    template<typename T__>\
-    friend const T__* target(const interface& i) noexcept\
-    {\
-        if(i._t == ::interface_detail::get_thunk<T__>())\
-            return reinterpret_cast<T__*>(i._ptr);\
-        else\
-            return nullptr;\
-    }\
-\
-    explicit operator bool() const noexcept { return _ptr; }\
-\
-    template<typename I__, std::enable_if_t<std::is_same_v<interface, std::decay_t<I__>>, bool> = false>\
-    bool operator==(I__&& rhs) const noexcept\
-    {\
-        if(!_ptr)\
-            return !rhs._ptr;\
-        if(::interface_detail::is_pointer_thunk(_t) && ::interface_detail::is_pointer_thunk(rhs._t))\
-            return *reinterpret_cast<void**>(_ptr) == *reinterpret_cast<void**>(rhs._ptr);\
-        return false;\
-    }\
-    template<typename I__, std::enable_if_t<std::is_same_v<interface, std::decay_t<I__>>, bool> = false>\
-    bool operator!=(I__&& rhs) const noexcept { return !(*this == rhs); }\
-\
-    friend void swap(interface& x, interface& y) noexcept\
-    {\
-        using ::std::swap;\
-        swap(x._ptr, y._ptr);\
-        swap(x._t, y._t);\
-        swap(x._vtable, y._vtable);\
-    }\
-\
-private:\
-    template<typename T__>\
-    using erasure_fn_t = typename ::interface_detail::erasure_fn<T__>::type;\
-    using vtable_t = ::std::tuple<{{template "vtable funcs" .}}>;\
-\
-    void* _ptr = nullptr;\
-    const ::interface_detail::thunk* _t = nullptr;\
-    vtable_t _vtable = {};\
+    A a{3};
+    I r = &a;
+    CHECK(r.f0() == 3 && held<A>(r) == &a);
+    I e;
+    CHECK(!e && !held<A>(e));
+    e = ::std::move(j);
+    CHECK(e.f0() == 1);
+    {{- if .Superset}}
+    S s = A{4};
+    I sub = {{if moveOnly}}::std::move(s){{else}}s{{end}};
+    {{- range .Methods}}
+    CHECK(sub.f{{.}}() == 4 + {{.}});
+    {{- end}}
+    CHECK(held<A>(sub) && held<A>(sub) != held<A>(s));
+    J chained = {{if moveOnly}}::std::move(sub){{else}}sub{{end}};
+    CHECK(chained.f0() == 4 && held<A>(chained) && held<A>(chained)->n == 4);
+    G g = [](const I& x) { return x.f0(); };
+    CHECK(g({{if moveOnly}}S(A{4}){{else}}s{{end}}) == 4);
+    {{- end}}
+    F f = [](int x) { return x + 1; };
+    CHECK(f(1) == 2);
+    selftest::geo::Shape flat = A{6};
+    selftest::ui::Shape shown = {{if moveOnly}}::std::move(flat){{else}}flat{{end}};
+    CHECK(shown.f0() == 6 && held<A>(shown) && held<A>(shown)->n == 6);
+    {{- if .Chain}}
+    C chain = A{0};
+    CHECK(chain.set(2).add(3).f0() == 5 && held<A>(chain)->n == 5);
+    P pair = A{8};
+    pair.at(0) = 9;
+    static_assert(::std::is_same_v<decltype(::std::as_const(pair).at(0)), const int&>, "const interfaces call const signatures.");
+    CHECK(held<A>(pair)->n == 9 && ::std::as_const(pair).at(0) == A::constant);
+    {{- end}}
+    {{- if refs}}
+    Q q = A{5};
+    static_assert(can_call_f0<Q>::value && !can_call_f0<Q&>::value, "f0 is only called on rvalues.");
+    CHECK(::std::move(q).f0() == 5);
+    {{- end}}
+    {{- if .Range}}
+    ::std::vector<int> v{1, 2, 3};
+    ::std::list<int> l{1, 2, 3};
+    CHECK(collect(&v) == v && collect(l) == v && collect(::std::vector<int>{}).empty());
+    {{- end}}
+    R large = Large{};
+    R forward = [](R x) { return x(R{}); };
+    int before = allocations;
+    CHECK(forward(::std::move(large)) == 1 && allocations == before);
+    E one = 1, two = 2, half = 0.5;
+    CHECK(one.type_id() == two.type_id() && one.type_id() != half.type_id());
+    I heap = B{A{7}, {}};
+    I& alias = heap;
+    B* object = held<B>(heap);
+    before = allocations;
+    {{- if not moveOnly}}
+    heap = alias;
+    CHECK(allocations == before && held<B>(heap) == object && heap.f0() == 7);
+    {{- end}}
+    heap = ::std::move(alias);
+    CHECK(allocations == before && held<B>(heap) == object && heap.f0() == 7);
+    return 0;
 }
 `
 
-var footer = `{{define "dash"}}
-    {{- range $k, $v := . -}}
-        {{if $k}}, {{end -}}
-        _{{.}}a, _{{.}}{{"b" -}}
-    {{end}}
-{{- end}}
-{{define "name dash"}}
-    {{- range $k, $v := . -}}
-        {{if $k}}, {{end -}}
-        INTERFACE_{{.}}, _{{. -}}
-    {{end}}
-{{- end}}
-// Overloaded macros through __VA_ARGS__ hacking.
-// Selects implementation by argument count.
-#define GET_INTERFACE_FROM({{template "dash" .}}, x, ...) x
-#define INTERFACE(...)\
-GET_INTERFACE_FROM(__VA_ARGS__, {{template "name dash" .}})(__VA_ARGS__)
+// selftestRejected lists flags that the generator must reject without writing a header, each
+// of which once produced a header failing to compile at its users.
+var selftestRejected = [][]string{
+	{"-N=0"},
+	{"-N=-1"},
+	{"-include=<foo"},
+	{`-include="cfg.h`},
+}
+
+// checkRejected runs the generator with each of selftestRejected, expecting it to fail with no output.
+func checkRejected() error {
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	for _, args := range selftestRejected {
+		out, err := exec.Command(self, args...).Output()
+		if _, failed := err.(*exec.ExitError); !failed || len(out) != 0 {
+			return fmt.Errorf("-selftest failed: %s was accepted", strings.Join(args, " "))
+		}
+	}
+	return nil
+}
+
+// selftestIncludes pairs flags with the standard header that what they emit needs, which the header
+// must include itself rather than rely on its users.
+var selftestIncludes = []struct{ Flag, Header string }{
+	{"-rtti", "<typeinfo>"},
+	{"-pmr", "<memory_resource>"},
+	{"-tag", "<cstdint>"},
+	{"-comparable", "<compare>"},
+	{"-stream=print", "<iosfwd>"},
+	{"-debug-checks", "<cassert>"},
+	{"-reflect", "<string_view>"},
+	{"-noexcept-boundary", "<system_error>"},
+}
+
+// checkIncludes runs the generator with each of selftestIncludes, expecting the header to include its header.
+func checkIncludes() error {
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	for _, c := range selftestIncludes {
+		out, err := exec.Command(self, "-N=1", c.Flag).Output()
+		if err != nil {
+			return fmt.Errorf("-selftest failed: %s: %v", c.Flag, err)
+		}
+		if !bytes.Contains(out, []byte("\n#include"+c.Header+"\n")) {
+			return fmt.Errorf("-selftest failed: %s doesn't include %s", c.Flag, c.Header)
+		}
+	}
+	return nil
+}
+
+// selftestLayouts lists the flags changing the layout of interfaces. -selftest compiles an interface
+// generated with -abi-assert and each of them, checking the layout that the generator computes.
+var selftestLayouts = [][]string{
+	{},
+	{"-pmr"},
+	{"-tag"},
+	{"-pmr", "-tag"},
+	{"-sbo=0"},
+	{"-sbo=0", "-pmr"},
+	{"-sbo=0", "-tag"},
+	{"-sbo=0", "-pmr", "-tag"},
+}
+
+// selftestLayout defines an interface, whose layout is asserted by compiling it.
+var selftestLayout = `#include "layout.hpp"
+
+using L = INTERFACE(int(), f);
+`
+
+// checkLayouts generates a header into dir with each of selftestLayouts, and compiles selftestLayout
+// against it with command.
+func checkLayouts(command []string, dir string) error {
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	source := filepath.Join(dir, "layout.cpp")
+	if err := ioutil.WriteFile(source, []byte(selftestLayout), 0666); err != nil {
+		return err
+	}
+	for _, flags := range selftestLayouts {
+		args := append([]string{"-N=1", "-abi-assert", "-o", filepath.Join(dir, "layout.hpp")}, flags...)
+		if out, err := exec.Command(self, args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("-selftest failed: %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+		c := append(command[:len(command):len(command)], "-std=c++17", "-c", "-o", filepath.Join(dir, "layout.o"), source)
+		cmd := exec.Command(c[0], c[1:]...)
+		cmd.Stdout = os.Stderr
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("-selftest failed: layout of -abi-assert %s: %v", strings.Join(flags, " "), err)
+		}
+	}
+	return nil
+}
+
+// selftestReserved defines an interface whose first method is named after the member function reset,
+// with the methods of I otherwise, which must fail to compile rather than have calls pick reset.
+var selftestReserved = `#include "interface.hpp"
 
+using I = INTERFACE({{range $k, $v := .}}{{if $k}}, {{end}}void(), {{if $k}}f{{$v}}{{else}}reset{{end}}{{end}});
 `
 
-var N = flag.Int("N", 8, "maximum number of methods in interface")
+// checkReserved compiles selftestReserved against the header in dir with command, expecting it to fail
+// on the assertion naming the method.
+func checkReserved(command []string, std string, dir string, methods []int) error {
+	source := filepath.Join(dir, "reserved.cpp")
+	err := writeFile(source, func(w io.Writer) error {
+		return renamed(w, func(w io.Writer) error {
+			return template.Must(template.New("").Parse(selftestReserved)).Execute(w, methods)
+		})
+	})
+	if err != nil {
+		return err
+	}
+	c := append(command[:len(command):len(command)], std, "-c", "-o", filepath.Join(dir, "reserved.o"), source)
+	out, err := exec.Command(c[0], c[1:]...).CombinedOutput()
+	if err == nil || !bytes.Contains(out, []byte("has the name of a member function")) {
+		return fmt.Errorf("-selftest failed: a method named reset was accepted\n%s", out)
+	}
+	return nil
+}
+
+// runSelftest generates the header into a temporary directory, checks that selftestReserved fails to
+// compile against it, then compiles and runs selftestProgram against it, with interfaces of the fewest
+// methods generated. It is skipped if there is no compiler.
+// The flags of selftestRejected and selftestIncludes are checked first, whether or not there is a compiler.
+func runSelftest(n int, only map[int]bool, interfaces []namedInterface, banner string) error {
+	if err := checkRejected(); err != nil {
+		return err
+	}
+	if err := checkIncludes(); err != nil {
+		return err
+	}
+
+	command := strings.Fields(*cxx)
+	if len(command) == 0 {
+		command = strings.Fields(os.Getenv("CXX"))
+	}
+	if len(command) == 0 {
+		command = []string{"c++"}
+	}
+	if _, err := exec.LookPath(command[0]); err != nil {
+		fmt.Fprintf(os.Stderr, "-selftest skipped, no compiler: %v\n", err)
+		return nil
+	}
+
+	dir, err := ioutil.TempDir("", "interface-selftest")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	if err := checkLayouts(command, dir); err != nil {
+		return err
+	}
+
+	t := selftestInterfaces{}
+	if !*defaultMoveOnly {
+		interfaces = append(interfaces[:len(interfaces):len(interfaces)], selftestRange)
+		t.Range = "::" + selftestRange.Namespace + "::" + selftestRange.Name
+	}
+	if err := run(filepath.Join(dir, "interface.hpp"), n, only, interfaces, banner); err != nil {
+		return err
+	}
+	m := 1
+	for m < n && only != nil && !only[m] {
+		m++
+	}
+	for i := 0; i < m; i++ {
+		t.Methods = append(t.Methods, i)
+		t.Reversed = append([]int{i}, t.Reversed...)
+	}
+	s := m + 1
+	for s <= n && only != nil && !only[s] {
+		s++
+	}
+	for i := s - 1; s <= n && i >= 0; i-- {
+		t.Superset = append(t.Superset, i)
+	}
+	c := 3
+	for c <= n && only != nil && !only[c] {
+		c++
+	}
+	for i := 0; c <= n && i < c-2; i++ {
+		t.Chain = append(t.Chain, i)
+	}
+	for i := 0; i < m || i < len(t.Superset) || i < len(t.Chain); i++ {
+		t.Members = append(t.Members, i)
+	}
+	std := "-std=c++17"
+	if *concepts || *comparable {
+		std = "-std=c++20"
+	}
+	if err := checkReserved(command, std, dir, t.Methods); err != nil {
+		return err
+	}
+
+	source := filepath.Join(dir, "selftest.cpp")
+	err = writeFile(source, func(w io.Writer) error {
+		return renamed(w, func(w io.Writer) error {
+			return template.Must(template.New("").Funcs(funcs()).Parse(selftestProgram)).Execute(w, t)
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	binary := filepath.Join(dir, "selftest")
+	args := append(command[1:], std, "-o", binary, source)
+	for _, c := range [][]string{append([]string{command[0]}, args...), {binary}} {
+		cmd := exec.Command(c[0], c[1:]...)
+		cmd.Stdout = os.Stderr
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("-selftest failed: %s: %v", strings.Join(c, " "), err)
+		}
+	}
+	fmt.Fprintln(os.Stderr, "-selftest passed")
+	return nil
+}
 
 func main() {
 	flag.Parse()
 
-	fmt.Println(header)
+	if *N < 1 || *N > maxN {
+		fmt.Fprintf(os.Stderr, "-N must be between 1 and %d\n", maxN)
+		os.Exit(2)
+	}
+	if *sbo < 0 {
+		fmt.Fprintln(os.Stderr, "-sbo must not be negative")
+		os.Exit(2)
+	}
+	if *relocatable && *sbo != 0 {
+		fmt.Fprintln(os.Stderr, "-relocatable requires -sbo=0, as objects stored inline are pointed to from within the interface")
+		os.Exit(2)
+	}
+	if !namespace.MatchString(*detailNamespace) {
+		fmt.Fprintln(os.Stderr, "-detail-namespace must be a namespace name")
+		os.Exit(2)
+	}
+	if *stream != "" && !identifier.MatchString(*stream) {
+		fmt.Fprintln(os.Stderr, "-stream must be a method name")
+		os.Exit(2)
+	}
+	if *guard != "" && *guard != "pragma" && !identifier.MatchString(*guard) {
+		fmt.Fprintln(os.Stderr, "-guard must be pragma or a macro name")
+		os.Exit(2)
+	}
+	if *visibilityClasses && *visibility == "" {
+		fmt.Fprintln(os.Stderr, "-visibility-classes requires -visibility")
+		os.Exit(2)
+	}
+	if *module != "" {
+		if !moduleName.MatchString(*module) {
+			fmt.Fprintln(os.Stderr, "-module must be a module name")
+			os.Exit(2)
+		}
+		if *manifestPath == "" || *guard != "" || *split || *selftest {
+			fmt.Fprintln(os.Stderr, "-module requires -manifest, and excludes -guard, -split and -selftest")
+			os.Exit(2)
+		}
+	}
+	if !identifier.MatchString(*prefix) {
+		fmt.Fprintln(os.Stderr, "-prefix must be a macro name")
+		os.Exit(2)
+	}
+	if *unique != "line" && *unique != "counter" {
+		fmt.Fprintln(os.Stderr, "-unique must be line or counter")
+		os.Exit(2)
+	}
+	if *defaultMoveOnly {
+		for k := range variants {
+			if variants[k].Macro == "INTERFACE" {
+				variants[k].Copyable = false
+			}
+		}
+	}
+	only, err := parseOnly(*onlyArities, *N)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if *selftest && (*output != "" || *split || *detailOut != "" || *macroOut != "") {
+		fmt.Fprintln(os.Stderr, "-selftest excludes -output, -split, -detail-out and -macro-out")
+		os.Exit(2)
+	}
+	if *split {
+		if *detailOut == "" || *macroOut == "" || *output != "" {
+			fmt.Fprintln(os.Stderr, "-split requires -detail-out and -macro-out, and excludes -output")
+			os.Exit(2)
+		}
+	} else if *detailOut != "" || *macroOut != "" {
+		fmt.Fprintln(os.Stderr, "-detail-out and -macro-out require -split")
+		os.Exit(2)
+	}
 
-	s := []int{}
-	tmp := template.Must(template.New("").Parse(interface_str))
-	for i := 0; i < *N; i++ {
-		s = append(s, i)
-		tmp.Execute(os.Stdout, s)
+	if *golden != "" && (*output != "" || *split || *selftest) {
+		fmt.Fprintln(os.Stderr, "-golden excludes -output, -split and -selftest")
+		os.Exit(2)
 	}
 
-	r := []int{}
-	for i := range s {
-		r = append(r, len(s)-i)
+	// The manifest is checked before writing anything.
+	var interfaces []namedInterface
+	if *manifestPath != "" {
+		if interfaces, err = loadManifest(*manifestPath); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	text, err := loadBanner(*banner)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if *golden != "" {
+		err = runGolden(*golden, *N, only, interfaces, text)
+	} else if *selftest {
+		err = runSelftest(*N, only, interfaces, text)
+	} else if *split {
+		err = runSplit(*detailOut, *macroOut, *N, only, interfaces, text)
+	} else {
+		err = run(*output, *N, only, interfaces, text)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
-	template.Must(template.New("").Parse(footer)).Execute(os.Stdout, r)
 }